@@ -30,6 +30,27 @@ type QueryClient interface {
 	ListModels(ctx context.Context) ([]string, error)
 }
 
+// JSONSchema describes the JSON a StructuredQueryClient response must match.
+type JSONSchema struct {
+	// Name identifies the schema to the provider (letters, digits, _ and -).
+	Name        string
+	Description string
+	// Schema is a JSON Schema object. Keep it to what every provider accepts:
+	// objects with all properties required and additionalProperties false,
+	// arrays, strings, numbers, integers and booleans.
+	Schema map[string]any
+}
+
+// StructuredQueryClient is implemented by query clients that can constrain a
+// response to a JSON schema. It is optional: callers type-assert for it and
+// fall back to describing the format in the prompt.
+type StructuredQueryClient interface {
+	QueryClient
+	// ChatJSON returns a single, non-streamed response that is a JSON document
+	// matching schema.
+	ChatJSON(ctx context.Context, model types.Model, query string, schema JSONSchema) (string, error)
+}
+
 // StreamResponse is the interface for streaming chat responses
 type StreamResponse interface {
 	// Next advances to the next chunk, returns true if there is more data
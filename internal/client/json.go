@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/austiecodes/gomor/internal/types"
+)
+
+// CompleteJSON asks model for JSON matching schema. Structured output is used
+// when qc supports it; otherwise, or if it fails, the response is streamed,
+// so the prompt itself must describe the expected JSON.
+func CompleteJSON(ctx context.Context, qc QueryClient, model types.Model, prompt string, schema JSONSchema) (string, error) {
+	if sc, ok := qc.(StructuredQueryClient); ok {
+		if response, err := sc.ChatJSON(ctx, model, prompt, schema); err == nil {
+			return response, nil
+		}
+	}
+
+	stream, err := qc.ChatStream(ctx, model, prompt)
+	if err != nil {
+		return "", err
+	}
+	return ReadStream(stream)
+}
+
+// ReadStream drains a stream into a string and closes it.
+func ReadStream(stream StreamResponse) (string, error) {
+	defer stream.Close()
+
+	var sb strings.Builder
+	for stream.Next() {
+		sb.WriteString(stream.GetChunk())
+	}
+	if err := stream.Err(); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// ExtractJSONObject returns the outermost {...} in s, tolerating code fences
+// and chatter around the JSON that some models add.
+func ExtractJSONObject(s string) string {
+	start := strings.Index(s, "{")
+	end := strings.LastIndex(s, "}")
+	if start < 0 || end < start {
+		return s
+	}
+	return s[start : end+1]
+}
@@ -1,13 +1,27 @@
 package commands
 
 import (
+	consolidatecmd "github.com/austiecodes/gomor/internal/commands/consolidate"
+	doctorcmd "github.com/austiecodes/gomor/internal/commands/doctor"
+	evalcmd "github.com/austiecodes/gomor/internal/commands/eval"
+	ingestcmd "github.com/austiecodes/gomor/internal/commands/ingest"
 	mcpcmd "github.com/austiecodes/gomor/internal/commands/mcp"
 	memorycmd "github.com/austiecodes/gomor/internal/commands/memory"
+	profilecmd "github.com/austiecodes/gomor/internal/commands/profile"
+	searchcmd "github.com/austiecodes/gomor/internal/commands/search"
 	setcmd "github.com/austiecodes/gomor/internal/commands/set"
+	whoamicmd "github.com/austiecodes/gomor/internal/commands/whoami"
 )
 
 func init() {
+	rootCmd.AddCommand(consolidatecmd.ConsolidateCmd)
+	rootCmd.AddCommand(doctorcmd.DoctorCmd)
+	rootCmd.AddCommand(evalcmd.EvalCmd)
+	rootCmd.AddCommand(ingestcmd.IngestCmd)
 	rootCmd.AddCommand(mcpcmd.McpCmd)
 	rootCmd.AddCommand(memorycmd.MemoryCmd)
+	rootCmd.AddCommand(profilecmd.ProfileCmd)
+	rootCmd.AddCommand(searchcmd.SearchCmd)
 	rootCmd.AddCommand(setcmd.SetCmd)
+	rootCmd.AddCommand(whoamicmd.WhoamiCmd)
 }
@@ -0,0 +1,111 @@
+package consolidate
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/austiecodes/gomor/internal/memory/consolidate"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/provider"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// ConsolidateCmd is the command to merge overlapping memories
+var ConsolidateCmd = &cobra.Command{
+	Use:   "consolidate",
+	Short: "Merge overlapping memories",
+	Long: `Group memories whose embeddings are similar, ask the tool model to write one merged
+memory for each group, and replace the group with it. The originals are kept as
+sources of the merged memory and archived, so retrieval no longer returns them.
+Chunks of ingested documents are left alone.
+
+The planned merges are shown as a diff (- original, + merged) and applied after
+you confirm. Use --dry-run to only show them, and --yes to apply them without
+asking, e.g. when running consolidation periodically from cron.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		similarity, _ := cmd.Flags().GetFloat64("similarity")
+		if err := runConsolidate(dryRun, yes, similarity); err != nil {
+			fmt.Fprintf(os.Stderr, "Consolidate error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	ConsolidateCmd.Flags().Bool("dry-run", false, "show the planned merges without applying them")
+	ConsolidateCmd.Flags().BoolP("yes", "y", false, "apply the merges without asking")
+	ConsolidateCmd.Flags().Float64("similarity", consolidate.DefaultSimilarity, "cosine similarity every pair of memories in a group must reach (0-1)")
+}
+
+func runConsolidate(dryRun, yes bool, similarity float64) error {
+	if similarity <= 0 || similarity > 1 {
+		return fmt.Errorf("--similarity must be between 0 and 1")
+	}
+
+	config, err := utils.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if config.Model.EmbeddingModel == nil {
+		return fmt.Errorf("embedding model not configured. Run 'gomor set' to configure")
+	}
+	if config.Model.ToolModel == nil {
+		return fmt.Errorf("tool model not configured. Run 'gomor set' to configure")
+	}
+
+	opts := consolidate.Options{
+		EmbeddingModel: *config.Model.EmbeddingModel,
+		ToolModel:      *config.Model.ToolModel,
+		Similarity:     similarity,
+	}
+	if opts.EmbeddingClient, err = provider.NewEmbeddingClient(config, opts.EmbeddingModel.Provider); err != nil {
+		return fmt.Errorf("failed to create embedding client: %w", err)
+	}
+	if opts.QueryClient, err = provider.NewQueryClient(config, opts.ToolModel.Provider); err != nil {
+		return fmt.Errorf("failed to create query client: %w", err)
+	}
+
+	memStore, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open memory store: %w", err)
+	}
+	defer memStore.Close()
+
+	ctx := context.Background()
+	plan, err := consolidate.NewPlan(ctx, memStore, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(consolidate.FormatPlan(plan))
+	if dryRun || len(plan.Merges) == 0 {
+		return nil
+	}
+
+	if !yes && !confirm(fmt.Sprintf("Apply %d merges? [y/N] ", len(plan.Merges))) {
+		fmt.Println("Nothing changed.")
+		return nil
+	}
+
+	if err := consolidate.Apply(ctx, memStore, plan, opts); err != nil {
+		return err
+	}
+	fmt.Printf("Merged %d memories into %d.\n", plan.Archived(), len(plan.Merges))
+	return nil
+}
+
+// confirm asks a yes/no question on stdin; anything but y or yes is no.
+func confirm(prompt string) bool {
+	fmt.Print(prompt)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
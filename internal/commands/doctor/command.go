@@ -0,0 +1,74 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/austiecodes/gomor/internal/memory/doctor"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/provider"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// DoctorCmd is the command to check and repair the memory store
+var DoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the memory store for corruption and inconsistencies",
+	Long: `Check the memory store: run SQLite's integrity check, compare the full-text index
+with the memories table, and flag memories whose embeddings don't match the configured
+embedding model or are not normalized. Use --fix to repair what can be repaired.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fix, _ := cmd.Flags().GetBool("fix")
+		healthy, err := runDoctor(fix)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Doctor error: %v\n", err)
+			os.Exit(1)
+		}
+		if !healthy {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	DoctorCmd.Flags().Bool("fix", false, "repair the problems that can be repaired")
+}
+
+func runDoctor(fix bool) (bool, error) {
+	config, err := utils.LoadConfig()
+	if err != nil {
+		return false, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	memStore, err := store.NewStore()
+	if err != nil {
+		return false, fmt.Errorf("failed to open memory store: %w", err)
+	}
+	defer memStore.Close()
+
+	opts := doctor.Options{Fix: fix}
+	if config.Model.EmbeddingModel != nil {
+		opts.EmbeddingModel = *config.Model.EmbeddingModel
+		if fix {
+			// Only needed to re-embed mismatched rows; normalization and FTS fixes work without it.
+			embClient, err := provider.NewEmbeddingClient(config, opts.EmbeddingModel.Provider)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: cannot re-embed memories: %v\n", err)
+			} else {
+				opts.EmbeddingClient = embClient
+			}
+		}
+	}
+
+	report, err := doctor.Run(context.Background(), memStore, opts)
+	if err != nil {
+		return false, err
+	}
+
+	fmt.Print(doctor.FormatReport(report, fix))
+	return report.Healthy(), nil
+}
@@ -0,0 +1,94 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/austiecodes/gomor/internal/memory/eval"
+	"github.com/austiecodes/gomor/internal/provider"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// EvalCmd is the command to measure retrieval quality on a labelled dataset
+var EvalCmd = &cobra.Command{
+	Use:   "eval <dataset.json>",
+	Short: "Evaluate retrieval quality on a dataset",
+	Long: `Load a dataset of memories and queries with the memory IDs each query should
+find into a temporary store, run every query through retrieval and report
+recall@k, MRR and nDCG@k for each configuration side by side.
+
+A dataset looks like:
+
+  {
+    "memories": [{"id": "editor", "text": "User edits code in Neovim", "tags": ["tools"]}],
+    "queries":  [{"query": "which editor do I use", "expected": ["editor"]}],
+    "configs":  [
+      {"name": "rrf"},
+      {"name": "weighted", "memory": {"fusion_strategy": "weighted", "min_similarity": 0.3}}
+    ]
+  }
+
+Each config overrides keys of the "memory" section of settings.json; without
+configs, the current settings are evaluated. By default memories and queries are
+embedded with a deterministic hashing embedder, so runs are offline and
+repeatable. Use --live to use the configured embedding and tool models instead.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		k, _ := cmd.Flags().GetInt("k")
+		live, _ := cmd.Flags().GetBool("live")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		if err := runEval(args[0], k, live, verbose); err != nil {
+			fmt.Fprintf(os.Stderr, "Eval error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	EvalCmd.Flags().Int("k", eval.DefaultK, "cutoff for recall@k and nDCG@k")
+	EvalCmd.Flags().Bool("live", false, "use the configured embedding and tool models instead of the offline embedder")
+	EvalCmd.Flags().BoolP("verbose", "v", false, "list the queries each configuration missed")
+}
+
+func runEval(path string, k int, live, verbose bool) error {
+	if k < 1 {
+		return fmt.Errorf("--k must be at least 1")
+	}
+
+	ds, err := eval.LoadDataset(path)
+	if err != nil {
+		return err
+	}
+
+	config, err := utils.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	opts := eval.Options{K: k}
+	if live {
+		if config.Model.EmbeddingModel == nil {
+			return fmt.Errorf("embedding model not configured. Run 'gomor set' to configure")
+		}
+		opts.EmbeddingModel = *config.Model.EmbeddingModel
+		opts.EmbeddingClient, err = provider.NewEmbeddingClient(config, opts.EmbeddingModel.Provider)
+		if err != nil {
+			return fmt.Errorf("failed to create embedding client: %w", err)
+		}
+		if config.Model.ToolModel != nil {
+			opts.ToolModel = *config.Model.ToolModel
+			opts.QueryClient, _ = provider.NewQueryClient(config, opts.ToolModel.Provider)
+		}
+	}
+
+	report, err := eval.Run(context.Background(), ds, config.Memory, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(eval.FormatReport(report, verbose))
+	return nil
+}
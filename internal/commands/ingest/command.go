@@ -0,0 +1,124 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/austiecodes/gomor/internal/memory/ingest"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/provider"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// IngestCmd is the command to load documents into memory
+var IngestCmd = &cobra.Command{
+	Use:   "ingest <path...>",
+	Short: "Load Markdown and text documents into memory",
+	Long: `Load Markdown (.md, .markdown, .mdx) and plain-text (.txt, .text) files into memory,
+so that long documents such as team conventions or ADRs can be retrieved. Directories
+are searched recursively, skipping hidden files and directories.
+
+Each document is split into chunks at its headings. A chunk starts with the headings
+it sits under, sections too long for one chunk are split at paragraphs with some
+overlap, and chunks are stored with source "document" and the path they came from.
+
+Ingesting a file again updates it in place: unchanged chunks keep their embeddings,
+and only new or changed chunks are embedded. A moved file is ingested as a new document.
+
+Example: gomor ingest --tags conventions docs/conventions.md docs/adr`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		tags, _ := cmd.Flags().GetString("tags")
+		size, _ := cmd.Flags().GetInt("chunk-size")
+		overlap, _ := cmd.Flags().GetInt("overlap")
+		if err := runIngest(args, tags, size, overlap); err != nil {
+			fmt.Fprintf(os.Stderr, "Ingest error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	IngestCmd.Flags().String("tags", "", "comma-separated tags to add to every chunk")
+	IngestCmd.Flags().Int("chunk-size", ingest.DefaultChunkSize, "maximum chunk length in characters")
+	IngestCmd.Flags().Int("overlap", ingest.DefaultChunkOverlap, "characters repeated between consecutive chunks of a long section")
+}
+
+func runIngest(paths []string, tags string, size, overlap int) error {
+	if size < 100 {
+		return fmt.Errorf("--chunk-size must be at least 100")
+	}
+	if overlap < 0 || overlap > size/4 {
+		return fmt.Errorf("--overlap must be between 0 and a quarter of --chunk-size")
+	}
+
+	files, err := ingest.CollectFiles(paths)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no Markdown or text files found")
+	}
+
+	config, err := utils.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if config.Model.EmbeddingModel == nil {
+		return fmt.Errorf("embedding model not configured. Run 'gomor set' to configure")
+	}
+	embeddingModel := *config.Model.EmbeddingModel
+	embClient, err := provider.NewEmbeddingClient(config, embeddingModel.Provider)
+	if err != nil {
+		return fmt.Errorf("failed to create embedding client: %w", err)
+	}
+
+	memStore, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open memory store: %w", err)
+	}
+	defer memStore.Close()
+
+	// Options read an overlap of 0 as the default and a negative one as none
+	if overlap == 0 {
+		overlap = -1
+	}
+	opts := ingest.Options{
+		EmbeddingClient: embClient,
+		EmbeddingModel:  embeddingModel,
+		ChunkSize:       size,
+		ChunkOverlap:    overlap,
+		Tags:            parseTags(tags),
+	}
+
+	failed := 0
+	for _, r := range ingest.Run(context.Background(), memStore, files, opts) {
+		switch {
+		case r.Err != nil:
+			failed++
+			fmt.Fprintf(os.Stderr, "%s: %v\n", r.Path, r.Err)
+		case r.Unchanged():
+			fmt.Printf("%s: %d chunks, unchanged\n", r.Path, r.Chunks)
+		default:
+			fmt.Printf("%s: %d chunks (%d embedded, %d unchanged, %d removed)\n", r.Path, r.Chunks, r.Embedded, r.Kept, r.Removed)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed", failed, len(files))
+	}
+	return nil
+}
+
+func parseTags(s string) []string {
+	var tags []string
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
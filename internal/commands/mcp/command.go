@@ -4,49 +4,173 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/spf13/cobra"
+
+	"github.com/austiecodes/gomor/internal/memory/profile"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/utils"
 )
 
 // McpCmd is the command to start the MCP server
 var McpCmd = &cobra.Command{
 	Use:   "mcp",
-	Short: "Start the MCP server over stdio",
-	Long:  `Start a Model Context Protocol (MCP) server that communicates over stdio. This allows gomor to be used as an MCP tool provider.`,
+	Short: "Start the MCP server over stdio or HTTP",
+	Long: `Start a Model Context Protocol (MCP) server that communicates over stdio. This allows gomor to be used as an MCP tool provider.
+
+With --http, the server instead listens on the given address, so one server can be
+shared by every client on a machine or in a container. Clients connect to /mcp with
+the bearer token set as mcp.http_token in settings.json; /healthz reports whether
+the server is up and needs no token. The server shuts down gracefully on SIGINT or
+SIGTERM.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := runMcpServer(); err != nil {
+		addr, _ := cmd.Flags().GetString("http")
+		if err := runMcpServer(addr); err != nil {
 			fmt.Fprintf(os.Stderr, "MCP server error: %v\n", err)
 			os.Exit(1)
 		}
 	},
 }
 
-func runMcpServer() error {
+func init() {
+	McpCmd.Flags().String("http", "", "serve streamable HTTP on this address, e.g. :8080, instead of stdio")
+}
+
+func runMcpServer(addr string) error {
+	configPath, err := utils.GetConfigPath()
+	if err != nil {
+		return err
+	}
+	memStore, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open memory store: %w", err)
+	}
+	defer memStore.Close()
+
+	// The store and clients are shared by every call for the life of the server
+	d := newDeps(memStore, configPath)
+	config, err := d.Config()
+	if err != nil {
+		return err
+	}
+	if addr != "" && config.MCP.HTTPToken == "" {
+		return fmt.Errorf("mcp.http_token is not set in settings.json; the HTTP server needs a bearer token")
+	}
+
+	server := newServer(d)
+	watcher, err := newMemoryWatcher(server, d)
+	if err != nil {
+		return err
+	}
+
+	// Serve until interrupted, watching for memory changes meanwhile
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go watcher.run(ctx)
+	if addr != "" {
+		return serveHTTP(ctx, addr, newHTTPHandler(ctx, server, d))
+	}
+	return server.Run(ctx, &mcp.StdioTransport{})
+}
+
+// newServer creates the MCP server with its tools, resources and prompts.
+func newServer(d *deps) *mcp.Server {
 	// Create the MCP server
 	server := mcp.NewServer(
 		&mcp.Implementation{
 			Name:    "gomor",
 			Version: "0.7.0",
 		},
-		nil,
+		&mcp.ServerOptions{
+			SubscribeHandler:   handleSubscribe,
+			UnsubscribeHandler: handleUnsubscribe,
+			CompletionHandler:  d.handleComplete,
+		},
 	)
 
 	// Register the memory_save tool
 	memorySaveTool := &mcp.Tool{
 		Name:        "memory_save",
-		Description: "Save a user preference or fact to memory. Use this to store declarative statements about user preferences, knowledge, or context.",
+		Description: "Save a user preference or fact to memory. Use this to store declarative statements about user preferences, knowledge, or context. Pin the memory, or give it a high importance, to include it in the user profile resource.",
 	}
-	mcp.AddTool(server, memorySaveTool, handleMemorySave)
+	mcp.AddTool(server, memorySaveTool, d.handleMemorySave)
 
 	// Register the memory_retrieve tool
 	memoryRetrieveTool := &mcp.Tool{
 		Name:        "memory_retrieve",
-		Description: "Retrieve relevant memories based on a query. Use this to recall user preferences, facts, or context that was previously saved.",
+		Description: "Retrieve relevant memories based on a query. Use this to recall user preferences, facts, or context that was previously saved. Optionally restrict results by tags, source, creation date and minimum score, or override how many are returned. Set explain to see why each memory was returned.",
 	}
-	mcp.AddTool(server, memoryRetrieveTool, handleMemoryRetrieve)
+	mcp.AddTool(server, memoryRetrieveTool, d.handleMemoryRetrieve)
 
-	// Start the stdio server
-	ctx := context.Background()
-	return server.Run(ctx, &mcp.StdioTransport{})
+	// Register the memory_extract tool
+	memoryExtractTool := &mcp.Tool{
+		Name:        "memory_extract",
+		Description: "Extract durable user facts and preferences from a conversation transcript, or from recent conversation history when no transcript is given. Memories that duplicate existing ones are skipped; the rest are saved with their confidence and wait for the user to approve them before memory_retrieve returns them.",
+	}
+	mcp.AddTool(server, memoryExtractTool, d.handleMemoryExtract)
+
+	// Register the memory_list tool
+	memoryListTool := &mcp.Tool{
+		Name:        "memory_list",
+		Description: "List memories newest first, a page at a time, optionally filtered by tags, source, status and creation date. Use this to browse memories and find their IDs.",
+	}
+	mcp.AddTool(server, memoryListTool, d.handleMemoryList)
+
+	// Register the memory_get tool
+	memoryGetTool := &mcp.Tool{
+		Name:        "memory_get",
+		Description: "Fetch a single memory by ID.",
+	}
+	mcp.AddTool(server, memoryGetTool, d.handleMemoryGet)
+
+	// Register the memory_update tool
+	memoryUpdateTool := &mcp.Tool{
+		Name:        "memory_update",
+		Description: "Correct a memory in place by ID: replace its text (the memory is re-embedded) or tags, or change whether it is pinned and its importance. Only the given parameters change; the ID and creation date are kept.",
+	}
+	mcp.AddTool(server, memoryUpdateTool, d.handleMemoryUpdate)
+
+	// Register the memory_delete tool
+	memoryDeleteTool := &mcp.Tool{
+		Name:        "memory_delete",
+		Description: "Delete a memory by ID, e.g. one that was saved by mistake. If the client supports elicitation, the user is asked to confirm first.",
+	}
+	mcp.AddTool(server, memoryDeleteTool, d.handleMemoryDelete)
+
+	// Register the memory_forget tool
+	memoryForgetTool := &mcp.Tool{
+		Name:        "memory_forget",
+		Description: "Forget everything about a topic, e.g. when the user says \"forget everything about my old job\". The first call finds the matching memories. If the client supports elicitation the user confirms deleting them; otherwise nothing is deleted and the candidates are returned with their IDs and a confirmation_token: show them to the user and call again with the confirmed IDs in confirm_ids and the token. Only IDs among those candidates are deleted. Deletions are recorded in an audit log.",
+	}
+	mcp.AddTool(server, memoryForgetTool, d.handleMemoryForget)
+
+	// Register the profile resource
+	profileResource := &mcp.Resource{
+		URI:         profile.URI,
+		Name:        "profile",
+		Title:       "User profile",
+		Description: "A short summary of who the user is, written from their pinned and most important memories. Read it at the start of a session; subscribe to be notified when it changes.",
+		MIMEType:    "text/markdown",
+	}
+	server.AddResource(profileResource, d.handleProfileRead)
+
+	// Register the memory resources: any memory can be read by ID, and the
+	// active ones are listed
+	memoryTemplate := &mcp.ResourceTemplate{
+		URITemplate: memoryURITemplate,
+		Name:        "memory",
+		Title:       "Memory",
+		Description: "A single memory by ID, as JSON. Subscribe to be notified when it changes.",
+		MIMEType:    "application/json",
+	}
+	server.AddResourceTemplate(memoryTemplate, d.handleMemoryRead)
+
+	// Register the prompts
+	server.AddPrompt(recallContextPrompt, d.handleRecallContext)
+	server.AddPrompt(rememberSessionPrompt, handleRememberSession)
+
+	return server
 }
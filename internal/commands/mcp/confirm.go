@@ -0,0 +1,34 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// confirmSchema asks for no fields: the user only accepts or declines.
+var confirmSchema = map[string]any{"type": "object", "properties": map[string]any{}}
+
+// canConfirm reports whether the client of session supports elicitation, so
+// destructive tools can ask the user before going ahead.
+func canConfirm(session *mcp.ServerSession) bool {
+	if session == nil {
+		return false
+	}
+	params := session.InitializeParams()
+	return params != nil && params.Capabilities != nil && params.Capabilities.Elicitation != nil
+}
+
+// confirm asks the user of session to confirm message through elicitation.
+// Declining or cancelling is not an error; it returns false.
+func confirm(ctx context.Context, session *mcp.ServerSession, message string) (bool, error) {
+	result, err := session.Elicit(ctx, &mcp.ElicitParams{
+		Message:         message,
+		RequestedSchema: confirmSchema,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to ask for confirmation: %w", err)
+	}
+	return result.Action == "accept", nil
+}
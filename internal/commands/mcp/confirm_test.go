@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// TestConfirm checks that confirmation is only asked of clients that support
+// elicitation, and that their answer is returned.
+func TestConfirm(t *testing.T) {
+	ctx := context.Background()
+
+	connect := func(opts *mcp.ClientOptions) *mcp.ServerSession {
+		t.Helper()
+		server := mcp.NewServer(&mcp.Implementation{Name: "gomor-test"}, nil)
+		client := mcp.NewClient(&mcp.Implementation{Name: "client"}, opts)
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		ss, err := server.Connect(ctx, serverTransport, nil)
+		if err != nil {
+			t.Fatalf("connect server: %v", err)
+		}
+		cs, err := client.Connect(ctx, clientTransport, nil)
+		if err != nil {
+			t.Fatalf("connect client: %v", err)
+		}
+		t.Cleanup(func() { cs.Close() })
+		return ss
+	}
+
+	if canConfirm(nil) {
+		t.Fatal("expected no confirmation without a session")
+	}
+	if canConfirm(connect(nil)) {
+		t.Fatal("expected no confirmation for a client without elicitation")
+	}
+
+	for _, action := range []string{"accept", "decline", "cancel"} {
+		var asked string
+		session := connect(&mcp.ClientOptions{
+			ElicitationHandler: func(ctx context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+				asked = req.Params.Message
+				return &mcp.ElicitResult{Action: action}, nil
+			},
+		})
+		if !canConfirm(session) {
+			t.Fatal("expected confirmation for a client with elicitation")
+		}
+		ok, err := confirm(ctx, session, "Delete this memory?")
+		if err != nil {
+			t.Fatalf("confirm: %v", err)
+		}
+		if asked != "Delete this memory?" {
+			t.Fatalf("unexpected message %q", asked)
+		}
+		if ok != (action == "accept") {
+			t.Fatalf("confirm returned %v for %s", ok, action)
+		}
+	}
+}
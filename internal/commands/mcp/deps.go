@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/provider"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// deps holds what the handlers share for the life of the server: the memory
+// store, opened once; the config, reloaded when settings.json changes; and
+// the provider clients, kept so their HTTP connections are reused.
+type deps struct {
+	store      *store.Store
+	configPath string
+
+	mu               sync.Mutex
+	config           *utils.Config
+	configStamp      fileStamp
+	embeddingClients map[string]client.EmbeddingClient
+	queryClients     map[string]client.QueryClient
+	forgetRequests   map[string]forgetRequest // by confirmation token
+}
+
+// fileStamp identifies a version of a file; the zero value means it doesn't exist.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// newDeps creates the dependencies of a server over s, configured by the
+// settings file at configPath. The store stays owned by the caller.
+func newDeps(s *store.Store, configPath string) *deps {
+	return &deps{store: s, configPath: configPath}
+}
+
+// Config returns the current config, loading it again first if settings.json
+// changed since the last call. It is shared and must not be modified.
+func (d *deps) Config() (*utils.Config, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.loadConfig()
+}
+
+// loadConfig is Config with d.mu held. Reloading drops the clients made
+// with the old config, whose keys or base URLs may have changed.
+func (d *deps) loadConfig() (*utils.Config, error) {
+	var stamp fileStamp
+	info, err := os.Stat(d.configPath)
+	switch {
+	case err == nil:
+		stamp = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if d.config != nil && stamp == d.configStamp {
+		return d.config, nil
+	}
+
+	config, err := utils.LoadConfigFrom(d.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	d.config = config
+	d.configStamp = stamp
+	d.embeddingClients = make(map[string]client.EmbeddingClient)
+	d.queryClients = make(map[string]client.QueryClient)
+	return config, nil
+}
+
+// EmbeddingClient returns the embedding client of a provider, creating it
+// on first use.
+func (d *deps) EmbeddingClient(providerName string) (client.EmbeddingClient, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	config, err := d.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := d.embeddingClients[providerName]; ok {
+		return c, nil
+	}
+	c, err := provider.NewEmbeddingClient(config, providerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedding client: %w", err)
+	}
+	d.embeddingClients[providerName] = c
+	return c, nil
+}
+
+// QueryClient returns the query client of a provider, creating it on first use.
+func (d *deps) QueryClient(providerName string) (client.QueryClient, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	config, err := d.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := d.queryClients[providerName]; ok {
+		return c, nil
+	}
+	c, err := provider.NewQueryClient(config, providerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create query client: %w", err)
+	}
+	d.queryClients[providerName] = c
+	return c, nil
+}
@@ -0,0 +1,143 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/austiecodes/gomor/internal/consts"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// newTestDeps returns deps over a store in a temporary directory, configured
+// by the user's settings.json so that tests calling providers can still run.
+func newTestDeps(t testing.TB) *deps {
+	t.Helper()
+	s, err := store.NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	configPath, err := utils.GetConfigPath()
+	if err != nil {
+		t.Fatalf("config path: %v", err)
+	}
+	return newDeps(s, configPath)
+}
+
+// writeConfig writes settings.json with an OpenAI key and memory_top_k, and
+// a distinct modification time so the change is noticed.
+func writeConfig(t testing.TB, path, apiKey string, topK int, modTime time.Time) {
+	t.Helper()
+	data := fmt.Sprintf(`{"providers": {"openai": {"api_key": %q}}, "memory": {"memory_top_k": %d}}`, apiKey, topK)
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("set config time: %v", err)
+	}
+}
+
+// TestDepsConfigReload checks that the config and clients are reused until
+// settings.json changes, and reloaded after.
+func TestDepsConfigReload(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "settings.json")
+	d := newDeps(nil, configPath)
+
+	config, err := d.Config()
+	if err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	if config.Memory.MemoryTopK != utils.DefaultConfig().Memory.MemoryTopK {
+		t.Fatalf("expected the default config without settings.json, got top k %d", config.Memory.MemoryTopK)
+	}
+
+	start := time.Now().Add(-time.Hour)
+	writeConfig(t, configPath, "key-1", 3, start)
+	config, err = d.Config()
+	if err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	if config.Memory.MemoryTopK != 3 {
+		t.Fatalf("expected settings.json to be loaded, got top k %d", config.Memory.MemoryTopK)
+	}
+	if again, _ := d.Config(); again != config {
+		t.Fatal("expected the unchanged config to be reused")
+	}
+
+	first, err := d.EmbeddingClient(consts.ProviderOpenAI)
+	if err != nil {
+		t.Fatalf("embedding client: %v", err)
+	}
+	if again, _ := d.EmbeddingClient(consts.ProviderOpenAI); again != first {
+		t.Fatal("expected the embedding client to be reused")
+	}
+
+	writeConfig(t, configPath, "key-2", 5, start.Add(time.Minute))
+	config, err = d.Config()
+	if err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	if config.Memory.MemoryTopK != 5 || config.Providers.OpenAI.APIKey != "key-2" {
+		t.Fatalf("expected the changed settings.json to be reloaded, got %+v", config.Memory)
+	}
+	if again, _ := d.EmbeddingClient(consts.ProviderOpenAI); again == first {
+		t.Fatal("expected a new embedding client after the config changed")
+	}
+}
+
+// BenchmarkToolCall compares the setup of a tool call, loading the config,
+// opening the store and creating the embedding client, done on every call
+// versus shared by the server. The call itself is a memory_list page.
+func BenchmarkToolCall(b *testing.B) {
+	ctx := context.Background()
+	dir := b.TempDir()
+	dbPath := filepath.Join(dir, "memory.db")
+	configPath := filepath.Join(dir, "settings.json")
+	writeConfig(b, configPath, "test-key", 10, time.Now().Add(-time.Hour))
+
+	s, err := store.NewStoreWithPath(dbPath)
+	if err != nil {
+		b.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+	for i := 0; i < 100; i++ {
+		if err := s.SaveMemory(&store.MemoryItem{Text: fmt.Sprintf("memory %d", i), Source: store.SourceExplicit}); err != nil {
+			b.Fatalf("save memory: %v", err)
+		}
+	}
+
+	call := func(d *deps) {
+		if _, err := d.EmbeddingClient(consts.ProviderOpenAI); err != nil {
+			b.Fatalf("embedding client: %v", err)
+		}
+		if _, _, err := d.handleMemoryList(ctx, &mcp.CallToolRequest{}, MemoryListInput{}); err != nil {
+			b.Fatalf("memory_list: %v", err)
+		}
+	}
+
+	b.Run("per-call", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			perCall, err := store.NewStoreWithPath(dbPath)
+			if err != nil {
+				b.Fatalf("open store: %v", err)
+			}
+			call(newDeps(perCall, configPath))
+			perCall.Close()
+		}
+	})
+
+	b.Run("shared", func(b *testing.B) {
+		d := newDeps(s, configPath)
+		for i := 0; i < b.N; i++ {
+			call(d)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// httpShutdownTimeout bounds how long requests in flight may take to finish
+// when the HTTP server shuts down.
+const httpShutdownTimeout = 10 * time.Second
+
+// newHTTPHandler serves server over streamable HTTP at /mcp to clients with
+// the bearer token of the current config, and reports liveness at /healthz
+// to anyone. When ctx is done, the streams clients hold open for
+// notifications are ended, so that shutting down only waits for requests in
+// flight.
+func newHTTPHandler(ctx context.Context, server *mcp.Server, d *deps) http.Handler {
+	streamable := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", auth.RequireBearerToken(d.verifyToken, nil)(endStreams(ctx, streamable)))
+	mux.HandleFunc("/healthz", handleHealthz)
+	return mux
+}
+
+// verifyToken accepts exactly the mcp.http_token of the current config, so
+// the token can be changed without restarting the server.
+func (d *deps) verifyToken(ctx context.Context, token string, req *http.Request) (*auth.TokenInfo, error) {
+	config, err := d.Config()
+	if err != nil {
+		return nil, err
+	}
+	want := config.MCP.HTTPToken
+	if want == "" || subtle.ConstantTimeCompare([]byte(token), []byte(want)) != 1 {
+		return nil, auth.ErrInvalidToken
+	}
+	// The token never expires; it only needs to outlive this request's check
+	return &auth.TokenInfo{Expiration: time.Now().Add(time.Minute)}, nil
+}
+
+// endStreams cancels GET requests, the standalone notification streams of
+// the streamable transport, when ctx is done. They never end on their own.
+func endStreams(ctx context.Context, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			reqCtx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+			stop := context.AfterFunc(ctx, cancel)
+			defer stop()
+			r = r.WithContext(reqCtx)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleHealthz reports that the server is up.
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+// serveHTTP serves handler on addr until ctx is done, then shuts down
+// gracefully: new connections are refused and requests in flight get
+// httpShutdownTimeout to finish.
+func serveHTTP(ctx context.Context, addr string, handler http.Handler) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	fmt.Fprintf(os.Stderr, "gomor MCP server listening on %s\n", listener.Addr())
+	return serveHTTPListener(ctx, listener, handler)
+}
+
+// serveHTTPListener is serveHTTP on an open listener.
+func serveHTTPListener(ctx context.Context, listener net.Listener, handler http.Handler) error {
+	httpServer := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- httpServer.Serve(listener) }()
+
+	select {
+	case err := <-errc:
+		return fmt.Errorf("HTTP server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		httpServer.Close()
+		return fmt.Errorf("failed to shut down HTTP server: %w", err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("HTTP server failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// bearerTransport adds a bearer token to every request.
+type bearerTransport struct {
+	token string
+}
+
+func (t bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// TestServeHTTP checks the health endpoint, that /mcp needs the token, and
+// that the server shuts down promptly with a client still connected.
+func TestServeHTTP(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	configPath := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(configPath, []byte(`{"mcp": {"http_token": "secret"}}`), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	d := newDeps(nil, configPath)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "gomor-test"}, nil)
+	server.AddPrompt(rememberSessionPrompt, handleRememberSession)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- serveHTTPListener(ctx, listener, newHTTPHandler(ctx, server, d)) }()
+	base := "http://" + listener.Addr().String()
+
+	resp, err := http.Get(base + "/healthz")
+	if err != nil {
+		t.Fatalf("healthz: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("healthz returned %d", resp.StatusCode)
+	}
+
+	for _, token := range []string{"", "wrong"} {
+		client := &http.Client{Transport: bearerTransport{token: token}}
+		resp, err := client.Post(base+"/mcp", "application/json", nil)
+		if err != nil {
+			t.Fatalf("post: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected 401 for token %q, got %d", token, resp.StatusCode)
+		}
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil)
+	session, err := client.Connect(ctx, &mcp.StreamableClientTransport{
+		Endpoint:   base + "/mcp",
+		HTTPClient: &http.Client{Transport: bearerTransport{token: "secret"}},
+		MaxRetries: -1,
+	}, nil)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer session.Close()
+	prompts, err := session.ListPrompts(ctx, nil)
+	if err != nil {
+		t.Fatalf("list prompts: %v", err)
+	}
+	if len(prompts.Prompts) != 1 || prompts.Prompts[0].Name != rememberSessionPrompt.Name {
+		t.Fatalf("unexpected prompts %v", prompts.Prompts)
+	}
+
+	start := time.Now()
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("serve: %v", err)
+		}
+	case <-time.After(httpShutdownTimeout):
+		t.Fatal("server did not shut down")
+	}
+	if elapsed := time.Since(start); elapsed > httpShutdownTimeout/2 {
+		t.Fatalf("shutdown took %v", elapsed)
+	}
+}
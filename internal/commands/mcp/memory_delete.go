@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MemoryDeleteInput defines the input schema for the memory delete tool
+type MemoryDeleteInput struct {
+	ID string `json:"id" jsonschema:"the ID of the memory to delete"`
+}
+
+// MemoryDeleteOutput defines the output schema for the memory delete tool
+type MemoryDeleteOutput struct {
+	Message string `json:"message" jsonschema:"what happened"`
+	Deleted bool   `json:"deleted" jsonschema:"false when the user declined the deletion"`
+}
+
+// handleMemoryDelete handles the memory_delete tool call. When the client
+// supports elicitation, the user is asked to confirm first. Deletions are
+// recorded in the audit log.
+func (d *deps) handleMemoryDelete(ctx context.Context, request *mcp.CallToolRequest, input MemoryDeleteInput) (*mcp.CallToolResult, MemoryDeleteOutput, error) {
+	id := strings.TrimSpace(input.ID)
+	if id == "" {
+		return nil, MemoryDeleteOutput{}, fmt.Errorf("parameter 'id' must be a non-empty string")
+	}
+
+	memory, err := d.store.GetMemory(id)
+	if err != nil {
+		return nil, MemoryDeleteOutput{}, err
+	}
+	if memory == nil {
+		return nil, MemoryDeleteOutput{}, fmt.Errorf("memory %s not found", id)
+	}
+
+	if canConfirm(request.Session) {
+		ok, err := confirm(ctx, request.Session, fmt.Sprintf("Delete this memory?\n\n%s", memory.Text))
+		if err != nil {
+			return nil, MemoryDeleteOutput{}, err
+		}
+		if !ok {
+			return nil, MemoryDeleteOutput{Message: fmt.Sprintf("Memory %s was kept: the user declined", id)}, nil
+		}
+	}
+
+	if _, err := d.store.DeleteMemoriesAudited([]string{id}, store.AuditDelete, "memory_delete"); err != nil {
+		return nil, MemoryDeleteOutput{}, err
+	}
+	return nil, MemoryDeleteOutput{
+		Message: fmt.Sprintf("Memory %s deleted", id),
+		Deleted: true,
+	}, nil
+}
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/austiecodes/gomor/internal/memory/extract"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultExtractHistoryLimit is how many history turns are read when no transcript is given.
+const defaultExtractHistoryLimit = 50
+
+// MemoryExtractInput defines the input schema for the memory extract tool
+type MemoryExtractInput struct {
+	Transcript   string `json:"transcript,omitempty" jsonschema:"the conversation to extract memories from, e.g. lines of 'user: ...' and 'assistant: ...'; when omitted, recent history is used"`
+	SessionID    string `json:"session_id,omitempty" jsonschema:"when no transcript is given, only use history from this session"`
+	HistoryLimit int    `json:"history_limit,omitempty" jsonschema:"when no transcript is given, how many recent history turns to read (default 50)"`
+}
+
+// ExtractedMemory is a memory saved for review
+type ExtractedMemory struct {
+	ID         string   `json:"id"`
+	Text       string   `json:"text"`
+	Tags       []string `json:"tags,omitempty"`
+	Confidence float64  `json:"confidence"`
+}
+
+// MemoryExtractOutput defines the output schema for the memory extract tool
+type MemoryExtractOutput struct {
+	Message       string              `json:"message" jsonschema:"summary of what was extracted"`
+	Pending       []ExtractedMemory   `json:"pending" jsonschema:"memories saved for review; they are not retrievable until approved"`
+	Duplicates    []extract.Duplicate `json:"duplicates,omitempty" jsonschema:"candidates skipped because a similar memory already exists"`
+	LowConfidence []extract.Candidate `json:"low_confidence,omitempty" jsonschema:"candidates skipped because the model was not confident enough"`
+}
+
+// handleMemoryExtract handles the memory_extract tool call
+func (d *deps) handleMemoryExtract(ctx context.Context, request *mcp.CallToolRequest, input MemoryExtractInput) (*mcp.CallToolResult, MemoryExtractOutput, error) {
+	if input.HistoryLimit < 0 {
+		return nil, MemoryExtractOutput{}, fmt.Errorf("parameter 'history_limit' must not be negative")
+	}
+	limit := input.HistoryLimit
+	if limit == 0 {
+		limit = defaultExtractHistoryLimit
+	}
+
+	config, err := d.Config()
+	if err != nil {
+		return nil, MemoryExtractOutput{}, err
+	}
+
+	if config.Model.EmbeddingModel == nil {
+		return nil, MemoryExtractOutput{}, fmt.Errorf("embedding model not configured. Run 'gomor set' to configure")
+	}
+	if config.Model.ToolModel == nil {
+		return nil, MemoryExtractOutput{}, fmt.Errorf("tool model not configured. Run 'gomor set' to configure")
+	}
+
+	transcript := strings.TrimSpace(input.Transcript)
+	if transcript == "" {
+		var history []store.HistoryItem
+		if input.SessionID != "" {
+			history, err = d.store.GetSessionHistory(input.SessionID, limit)
+		} else {
+			history, err = d.store.GetRecentHistory(limit)
+		}
+		if err != nil {
+			return nil, MemoryExtractOutput{}, err
+		}
+		if len(history) == 0 {
+			return nil, MemoryExtractOutput{}, fmt.Errorf("no transcript given and no conversation history found")
+		}
+		transcript = extract.FormatTranscript(history)
+	}
+
+	// Create clients
+	embeddingModel := *config.Model.EmbeddingModel
+	embClient, err := d.EmbeddingClient(embeddingModel.Provider)
+	if err != nil {
+		return nil, MemoryExtractOutput{}, err
+	}
+	toolModel := *config.Model.ToolModel
+	queryClient, err := d.QueryClient(toolModel.Provider)
+	if err != nil {
+		return nil, MemoryExtractOutput{}, err
+	}
+
+	extractor := extract.NewExtractor(d.store, embClient, queryClient, embeddingModel, toolModel, config.Memory)
+	result, err := extractor.Extract(ctx, transcript)
+	if err != nil {
+		return nil, MemoryExtractOutput{}, err
+	}
+
+	output := MemoryExtractOutput{
+		Message:       extractMessage(result),
+		Pending:       []ExtractedMemory{},
+		Duplicates:    result.Duplicates,
+		LowConfidence: result.LowConfidence,
+	}
+	for _, item := range result.Saved {
+		output.Pending = append(output.Pending, ExtractedMemory{
+			ID:         item.ID,
+			Text:       item.Text,
+			Tags:       item.Tags,
+			Confidence: item.Confidence,
+		})
+	}
+	return nil, output, nil
+}
+
+// extractMessage summarizes an extraction result.
+func extractMessage(result *extract.Result) string {
+	msg := fmt.Sprintf("Extracted %d memories; they will be retrievable once approved in the review queue of 'gomor memory'", len(result.Saved))
+	var skipped []string
+	if n := len(result.Duplicates); n > 0 {
+		skipped = append(skipped, fmt.Sprintf("%d duplicates", n))
+	}
+	if n := len(result.LowConfidence); n > 0 {
+		skipped = append(skipped, fmt.Sprintf("%d low-confidence candidates", n))
+	}
+	if len(skipped) > 0 {
+		msg += fmt.Sprintf(" (skipped %s)", strings.Join(skipped, " and "))
+	}
+	return msg
+}
@@ -0,0 +1,242 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// forgetTokenTTL is how long the candidates found by memory_forget can be
+// confirmed for deletion.
+const forgetTokenTTL = 10 * time.Minute
+
+// forgetRequest is the candidate set of a memory_forget call, waiting for the
+// user to confirm which of its memories to delete.
+type forgetRequest struct {
+	query      string
+	candidates map[string]bool
+	expires    time.Time
+}
+
+// MemoryForgetInput defines the input schema for the memory forget tool
+type MemoryForgetInput struct {
+	Query      string   `json:"query" jsonschema:"what to forget, e.g. 'my old job at Acme'; recorded in the audit log"`
+	Tags       string   `json:"tags,omitempty" jsonschema:"comma-separated tags; only memories with all of these tags are candidates"`
+	Source     string   `json:"source,omitempty" jsonschema:"only consider memories from this source: explicit, extracted or document"`
+	TopK       int      `json:"top_k,omitempty" jsonschema:"maximum number of candidates (defaults to the configured memory_top_k)"`
+	ConfirmIDs []string `json:"confirm_ids,omitempty" jsonschema:"IDs of candidates the user confirmed; only these are deleted"`
+	Token      string   `json:"confirmation_token,omitempty" jsonschema:"the confirmation_token returned with the candidates; required with confirm_ids"`
+}
+
+// ForgetCandidate is a memory that matched a memory_forget query.
+type ForgetCandidate struct {
+	ID    string   `json:"id" jsonschema:"the ID of the memory"`
+	Text  string   `json:"text" jsonschema:"the memory text"`
+	Tags  []string `json:"tags,omitempty" jsonschema:"the memory tags"`
+	Score float64  `json:"score" jsonschema:"how well the memory matched the query, from 0 to 1"`
+}
+
+// MemoryForgetOutput defines the output schema for the memory forget tool
+type MemoryForgetOutput struct {
+	Message           string            `json:"message" jsonschema:"what happened and what to do next"`
+	Candidates        []ForgetCandidate `json:"candidates,omitempty" jsonschema:"the memories matching the query"`
+	Deleted           []string          `json:"deleted,omitempty" jsonschema:"IDs of the memories deleted"`
+	Rejected          []string          `json:"rejected,omitempty" jsonschema:"confirmed IDs that were not deleted because they were not among the candidates"`
+	NeedsConfirmation bool              `json:"needs_confirmation,omitempty" jsonschema:"true when nothing was deleted yet: show the candidates to the user and call again with the confirmed IDs in confirm_ids and the confirmation_token"`
+	Token             string            `json:"confirmation_token,omitempty" jsonschema:"pass this back with confirm_ids to delete some of the candidates; it expires after 10 minutes"`
+}
+
+// handleMemoryForget handles the memory_forget tool call. The first call
+// retrieves the memories matching the query. When the client supports
+// elicitation the user confirms deleting them right away; otherwise they are
+// returned with a confirmation token, and a second call deletes the IDs in
+// confirm_ids that are among the candidates of that token. Deletions are
+// recorded in the audit log.
+func (d *deps) handleMemoryForget(ctx context.Context, request *mcp.CallToolRequest, input MemoryForgetInput) (*mcp.CallToolResult, MemoryForgetOutput, error) {
+	query := strings.TrimSpace(input.Query)
+	if query == "" {
+		return nil, MemoryForgetOutput{}, fmt.Errorf("parameter 'query' must be a non-empty string")
+	}
+	var confirmed []string
+	for _, id := range input.ConfirmIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			confirmed = append(confirmed, id)
+		}
+	}
+
+	// Second call: delete the confirmed IDs that the first call found
+	if len(confirmed) > 0 {
+		return d.forgetConfirmed(ctx, request, input.Token, confirmed)
+	}
+
+	text, opts, err := parseRetrieveInput(MemoryRetrieveInput{Query: query, Tags: input.Tags, Source: input.Source, TopK: input.TopK})
+	if err != nil {
+		return nil, MemoryForgetOutput{}, err
+	}
+
+	config, err := d.Config()
+	if err != nil {
+		return nil, MemoryForgetOutput{}, err
+	}
+
+	ret, err := d.newRetriever(config)
+	if err != nil {
+		return nil, MemoryForgetOutput{}, err
+	}
+	response, err := ret.RetrieveWithOptions(ctx, text, opts)
+	if err != nil {
+		return nil, MemoryForgetOutput{}, fmt.Errorf("retrieval failed: %w", err)
+	}
+
+	var candidates []ForgetCandidate
+	for _, r := range response.Results {
+		candidates = append(candidates, ForgetCandidate{ID: r.Item.ID, Text: r.Item.Text, Tags: r.Item.Tags, Score: r.Score})
+	}
+	if len(candidates) == 0 {
+		return nil, MemoryForgetOutput{Message: "No memories match the query; nothing to forget"}, nil
+	}
+
+	ids := make([]string, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.ID
+	}
+	token := d.saveForgetRequest(query, ids)
+
+	if !canConfirm(request.Session) {
+		return nil, MemoryForgetOutput{
+			Message:           fmt.Sprintf("Found %d memories. Nothing was deleted: ask the user which to forget, then call memory_forget again with their IDs in confirm_ids and the confirmation_token.", len(candidates)),
+			Candidates:        candidates,
+			NeedsConfirmation: true,
+			Token:             token,
+		}, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Forget these %d memories about %q?\n", len(candidates), query))
+	for _, c := range candidates {
+		sb.WriteString("\n- " + c.Text)
+	}
+	ok, err := confirm(ctx, request.Session, sb.String())
+	if err != nil {
+		return nil, MemoryForgetOutput{}, err
+	}
+	if !ok {
+		return nil, MemoryForgetOutput{
+			Message:    "The user declined; nothing was deleted. To forget only some of the candidates, call memory_forget again with their IDs in confirm_ids and the confirmation_token.",
+			Candidates: candidates,
+			Token:      token,
+		}, nil
+	}
+
+	d.takeForgetRequest(token)
+	deleted, err := d.store.DeleteMemoriesAudited(ids, store.AuditForget, query)
+	if err != nil {
+		return nil, MemoryForgetOutput{}, err
+	}
+	return nil, MemoryForgetOutput{
+		Message:    fmt.Sprintf("Forgot %d memories", len(deleted)),
+		Candidates: candidates,
+		Deleted:    deleted,
+	}, nil
+}
+
+// forgetConfirmed deletes the confirmed IDs that are among the candidates of
+// token, after asking the user when the client supports elicitation. The
+// other IDs are returned as rejected.
+func (d *deps) forgetConfirmed(ctx context.Context, request *mcp.CallToolRequest, token string, confirmed []string) (*mcp.CallToolResult, MemoryForgetOutput, error) {
+	req, ok := d.takeForgetRequest(token)
+	if !ok {
+		return nil, MemoryForgetOutput{}, fmt.Errorf("parameter 'confirmation_token' is missing or expired; call memory_forget without confirm_ids to find the candidates again")
+	}
+
+	var accepted, rejected []string
+	for _, id := range confirmed {
+		if req.candidates[id] {
+			accepted = append(accepted, id)
+		} else {
+			rejected = append(rejected, id)
+		}
+	}
+	if len(accepted) == 0 {
+		return nil, MemoryForgetOutput{
+			Message:  "None of the confirmed IDs were candidates of this query; nothing was deleted",
+			Rejected: rejected,
+		}, nil
+	}
+
+	if canConfirm(request.Session) {
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("Forget these %d memories about %q?\n", len(accepted), req.query))
+		for _, id := range accepted {
+			memory, err := d.store.GetMemory(id)
+			if err != nil {
+				return nil, MemoryForgetOutput{}, err
+			}
+			if memory != nil {
+				sb.WriteString("\n- " + memory.Text)
+			}
+		}
+		ok, err := confirm(ctx, request.Session, sb.String())
+		if err != nil {
+			return nil, MemoryForgetOutput{}, err
+		}
+		if !ok {
+			return nil, MemoryForgetOutput{Message: "The user declined; nothing was deleted", Rejected: rejected}, nil
+		}
+	}
+
+	deleted, err := d.store.DeleteMemoriesAudited(accepted, store.AuditForget, req.query)
+	if err != nil {
+		return nil, MemoryForgetOutput{}, err
+	}
+	return nil, MemoryForgetOutput{
+		Message:  fmt.Sprintf("Forgot %d of %d confirmed memories", len(deleted), len(confirmed)),
+		Deleted:  deleted,
+		Rejected: rejected,
+	}, nil
+}
+
+// saveForgetRequest remembers the candidates found for query until they are
+// confirmed or expire, and returns the token that identifies them.
+func (d *deps) saveForgetRequest(query string, ids []string) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	if d.forgetRequests == nil {
+		d.forgetRequests = make(map[string]forgetRequest)
+	}
+	for token, req := range d.forgetRequests {
+		if now.After(req.expires) {
+			delete(d.forgetRequests, token)
+		}
+	}
+
+	req := forgetRequest{query: query, candidates: make(map[string]bool), expires: now.Add(forgetTokenTTL)}
+	for _, id := range ids {
+		req.candidates[id] = true
+	}
+	token := uuid.New().String()
+	d.forgetRequests[token] = req
+	return token
+}
+
+// takeForgetRequest returns the candidates of token and forgets them, so
+// each set of candidates is confirmed at most once.
+func (d *deps) takeForgetRequest(token string) (forgetRequest, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	req, ok := d.forgetRequests[token]
+	delete(d.forgetRequests, token)
+	if !ok || time.Now().After(req.expires) {
+		return forgetRequest{}, false
+	}
+	return req, true
+}
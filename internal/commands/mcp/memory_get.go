@@ -0,0 +1,47 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MemoryGetInput defines the input schema for the memory get tool
+type MemoryGetInput struct {
+	ID string `json:"id" jsonschema:"the ID of the memory"`
+}
+
+// MemoryGetOutput defines the output schema for the memory get tool
+type MemoryGetOutput struct {
+	Memory     store.MemoryItem `json:"memory" jsonschema:"the memory"`
+	MergedFrom []string         `json:"merged_from,omitempty" jsonschema:"IDs of the archived memories consolidated into this one"`
+}
+
+// handleMemoryGet handles the memory_get tool call
+func (d *deps) handleMemoryGet(ctx context.Context, request *mcp.CallToolRequest, input MemoryGetInput) (*mcp.CallToolResult, MemoryGetOutput, error) {
+	id := strings.TrimSpace(input.ID)
+	if id == "" {
+		return nil, MemoryGetOutput{}, fmt.Errorf("parameter 'id' must be a non-empty string")
+	}
+
+	memory, err := d.store.GetMemory(id)
+	if err != nil {
+		return nil, MemoryGetOutput{}, err
+	}
+	if memory == nil {
+		return nil, MemoryGetOutput{}, fmt.Errorf("memory %s not found", id)
+	}
+
+	sources, err := d.store.GetMemorySources(id)
+	if err != nil {
+		return nil, MemoryGetOutput{}, err
+	}
+	output := MemoryGetOutput{Memory: *memory}
+	for _, s := range sources {
+		output.MergedFrom = append(output.MergedFrom, s.ID)
+	}
+	return nil, output, nil
+}
@@ -0,0 +1,115 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/austiecodes/gomor/internal/memory/retrieval"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Page sizes of memory_list.
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+// MemoryListInput defines the input schema for the memory list tool
+type MemoryListInput struct {
+	Tags          string `json:"tags,omitempty" jsonschema:"comma-separated tags; only memories with all of these tags are listed"`
+	Source        string `json:"source,omitempty" jsonschema:"only list memories from this source: explicit, extracted or document"`
+	Status        string `json:"status,omitempty" jsonschema:"only list memories with this status: active (the default), pending, archived or all"`
+	CreatedAfter  string `json:"created_after,omitempty" jsonschema:"only list memories created on or after this date (YYYY-MM-DD or RFC 3339)"`
+	CreatedBefore string `json:"created_before,omitempty" jsonschema:"only list memories created before this date (YYYY-MM-DD or RFC 3339)"`
+	Limit         int    `json:"limit,omitempty" jsonschema:"maximum number of memories to return, up to 100 (defaults to 20)"`
+	Offset        int    `json:"offset,omitempty" jsonschema:"number of memories to skip, for paging"`
+}
+
+// MemoryListOutput defines the output schema for the memory list tool
+type MemoryListOutput struct {
+	Memories   []store.MemoryItem `json:"memories" jsonschema:"the memories on this page, newest first"`
+	Total      int                `json:"total" jsonschema:"how many memories match the filters"`
+	NextOffset int                `json:"next_offset,omitempty" jsonschema:"the offset of the next page; absent on the last page"`
+}
+
+// handleMemoryList handles the memory_list tool call
+func (d *deps) handleMemoryList(ctx context.Context, request *mcp.CallToolRequest, input MemoryListInput) (*mcp.CallToolResult, MemoryListOutput, error) {
+	filter, err := parseMemoryFilter(input.Tags, input.Source, input.CreatedAfter, input.CreatedBefore)
+	if err != nil {
+		return nil, MemoryListOutput{}, err
+	}
+	status, err := parseStatus(input.Status)
+	if err != nil {
+		return nil, MemoryListOutput{}, err
+	}
+
+	if input.Limit < 0 || input.Limit > maxListLimit {
+		return nil, MemoryListOutput{}, fmt.Errorf("parameter 'limit' must be between 1 and %d", maxListLimit)
+	}
+	limit := input.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	if input.Offset < 0 {
+		return nil, MemoryListOutput{}, fmt.Errorf("parameter 'offset' must not be negative")
+	}
+
+	memories, total, err := d.store.ListMemories(filter, status, limit, input.Offset)
+	if err != nil {
+		return nil, MemoryListOutput{}, err
+	}
+
+	output := MemoryListOutput{Memories: memories, Total: total}
+	if output.Memories == nil {
+		output.Memories = []store.MemoryItem{}
+	}
+	if next := input.Offset + len(memories); next < total {
+		output.NextOffset = next
+	}
+	return nil, output, nil
+}
+
+// parseMemoryFilter builds a filter from the tags, source and creation date
+// parameters shared by the memory tools.
+func parseMemoryFilter(tags, source, createdAfter, createdBefore string) (store.MemoryFilter, error) {
+	filter := store.MemoryFilter{Tags: splitTags(tags)}
+
+	var err error
+	if filter.Source, err = retrieval.ParseSource(source); err != nil {
+		return filter, fmt.Errorf("parameter 'source': %w", err)
+	}
+	if strings.TrimSpace(createdAfter) != "" {
+		if filter.CreatedAfter, err = retrieval.ParseDate(createdAfter); err != nil {
+			return filter, fmt.Errorf("parameter 'created_after': %w", err)
+		}
+	}
+	if strings.TrimSpace(createdBefore) != "" {
+		if filter.CreatedBefore, err = retrieval.ParseDate(createdBefore); err != nil {
+			return filter, fmt.Errorf("parameter 'created_before': %w", err)
+		}
+	}
+	if !filter.CreatedAfter.IsZero() && !filter.CreatedBefore.IsZero() &&
+		!filter.CreatedAfter.Before(filter.CreatedBefore) {
+		return filter, fmt.Errorf("parameter 'created_after' must be earlier than 'created_before'")
+	}
+	return filter, nil
+}
+
+// parseStatus parses the status parameter of memory_list. Empty means
+// active, and "all" is returned as the empty status, which matches any.
+func parseStatus(s string) (store.MemoryStatus, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", string(store.StatusActive):
+		return store.StatusActive, nil
+	case string(store.StatusPending):
+		return store.StatusPending, nil
+	case string(store.StatusArchived):
+		return store.StatusArchived, nil
+	case "all":
+		return "", nil
+	default:
+		return "", fmt.Errorf("parameter 'status' must be active, pending, archived or all, got %q", s)
+	}
+}
@@ -0,0 +1,250 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/austiecodes/gomor/internal/memory/profile"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Memory resources are addressed as memory://<id>.
+const (
+	memoryURIPrefix   = "memory://"
+	memoryURITemplate = "memory://{id}"
+)
+
+// The change log is polled for changes made by any process, and entries
+// older than changeRetention are pruned.
+const (
+	changePollInterval = 2 * time.Second
+	changeBatchSize    = 500
+	changeRetention    = 24 * time.Hour
+)
+
+// memoryURI returns the resource URI of a memory.
+func memoryURI(id string) string {
+	return memoryURIPrefix + id
+}
+
+// memoryResource describes a memory for resources/list.
+func memoryResource(m store.MemoryItem) *mcp.Resource {
+	title := strings.Join(strings.Fields(m.Text), " ")
+	if runes := []rune(title); len(runes) > 80 {
+		title = string(runes[:77]) + "..."
+	}
+
+	description := fmt.Sprintf("%s memory from %s", m.Source, m.CreatedAt.Format("2006-01-02"))
+	if len(m.Tags) > 0 {
+		description += fmt.Sprintf(" (tags: %s)", strings.Join(m.Tags, ", "))
+	}
+
+	return &mcp.Resource{
+		URI:         memoryURI(m.ID),
+		Name:        m.ID,
+		Title:       title,
+		Description: description,
+		MIMEType:    "application/json",
+	}
+}
+
+// listedMemory reports whether a memory is listed as a resource: active
+// memories are, except chunks of ingested documents, which can number in the
+// thousands and are only read by ID.
+func listedMemory(m store.MemoryItem) bool {
+	return (m.Status == "" || m.Status == store.StatusActive) && m.DocumentID == ""
+}
+
+// handleMemoryRead handles reads of memory://{id}. Memories of any status
+// can be read by ID, though only active ones are listed.
+func (d *deps) handleMemoryRead(ctx context.Context, request *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	id, ok := strings.CutPrefix(request.Params.URI, memoryURIPrefix)
+	if !ok || id == "" {
+		return nil, mcp.ResourceNotFoundError(request.Params.URI)
+	}
+
+	memory, err := d.store.GetMemory(id)
+	if err != nil {
+		return nil, err
+	}
+	if memory == nil {
+		return nil, mcp.ResourceNotFoundError(request.Params.URI)
+	}
+
+	data, err := json.MarshalIndent(memory, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal memory: %w", err)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		}},
+	}, nil
+}
+
+// memoryWatcher keeps the server's memory resources in step with the store.
+// It polls the change log, so it sees changes made through any path: MCP
+// tools, the TUI, ingest, consolidation or another gomor process.
+type memoryWatcher struct {
+	server  *mcp.Server
+	deps    *deps
+	lastSeq int64
+
+	// A profile refresh runs at most once at a time; changes seen meanwhile
+	// make it run once more.
+	mu             sync.Mutex
+	refreshing     bool
+	refreshPending bool
+}
+
+// newMemoryWatcher lists the active memories as resources, leaving out
+// document chunks, and starts watching from the latest logged change.
+func newMemoryWatcher(server *mcp.Server, d *deps) (*memoryWatcher, error) {
+	w := &memoryWatcher{server: server, deps: d}
+
+	// Read the position first, so changes made while listing are seen again
+	seq, err := d.store.LatestMemoryChange()
+	if err != nil {
+		return nil, err
+	}
+	w.lastSeq = seq
+
+	memories, err := d.store.GetMemories(store.MemoryFilter{})
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range memories {
+		if listedMemory(m) {
+			server.AddResource(memoryResource(m), d.handleMemoryRead)
+		}
+	}
+	return w, nil
+}
+
+// refreshProfile regenerates the profile in the background and then
+// notifies its subscribers. They are notified even if regenerating fails,
+// since the stored version may still be out of date. If a refresh is already
+// running, another one follows it.
+func (w *memoryWatcher) refreshProfile(ctx context.Context) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.refreshing {
+		w.refreshPending = true
+		return
+	}
+	w.refreshing = true
+
+	go func() {
+		for {
+			refreshCtx, cancel := context.WithTimeout(ctx, profileRefreshTimeout)
+			if _, _, err := w.deps.refreshProfile(refreshCtx); err != nil {
+				fmt.Fprintf(os.Stderr, "memory watcher: failed to refresh profile: %v\n", err)
+			}
+			cancel()
+			w.server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: profile.URI})
+
+			w.mu.Lock()
+			if !w.refreshPending || ctx.Err() != nil {
+				w.refreshing = false
+				w.mu.Unlock()
+				return
+			}
+			w.refreshPending = false
+			w.mu.Unlock()
+		}
+	}()
+}
+
+// run polls for changes until ctx is done.
+func (w *memoryWatcher) run(ctx context.Context) {
+	ticker := time.NewTicker(changePollInterval)
+	defer ticker.Stop()
+
+	lastPrune := time.Time{}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := w.poll(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "memory watcher: %v\n", err)
+		}
+		if time.Since(lastPrune) > time.Hour {
+			if err := w.deps.store.PruneMemoryChanges(time.Now().Add(-changeRetention)); err != nil {
+				fmt.Fprintf(os.Stderr, "memory watcher: %v\n", err)
+			}
+			lastPrune = time.Now()
+		}
+	}
+}
+
+// poll applies the changes logged since the last poll: changed memories are
+// added to or removed from the resource list, and subscribers of each
+// changed memory are notified. If the profile depends on one, it is
+// regenerated in the background and its subscribers notified after.
+func (w *memoryWatcher) poll(ctx context.Context) error {
+	for {
+		changes, err := w.deps.store.GetMemoryChanges(w.lastSeq, changeBatchSize)
+		if err != nil || len(changes) == 0 {
+			return err
+		}
+
+		config, err := w.deps.Config()
+		if err != nil {
+			return err
+		}
+		current, err := w.deps.store.GetProfile()
+		if err != nil {
+			return err
+		}
+		inProfile := make(map[string]bool)
+		if current != nil {
+			for _, id := range current.MemoryIDs {
+				inProfile[id] = true
+			}
+		}
+
+		seen := make(map[string]bool)
+		profileChanged := false
+		for _, c := range changes {
+			if seen[c.MemoryID] {
+				continue
+			}
+			seen[c.MemoryID] = true
+
+			memory, err := w.deps.store.GetMemory(c.MemoryID)
+			if err != nil {
+				return err
+			}
+			uri := memoryURI(c.MemoryID)
+			if memory != nil && listedMemory(*memory) {
+				w.server.AddResource(memoryResource(*memory), w.deps.handleMemoryRead)
+			} else {
+				w.server.RemoveResources(uri)
+			}
+			w.server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri})
+
+			if inProfile[c.MemoryID] || (memory != nil && profile.Qualifies(*memory, config.Memory)) {
+				profileChanged = true
+			}
+		}
+		if profileChanged {
+			w.refreshProfile(ctx)
+		}
+
+		w.lastSeq = changes[len(changes)-1].Seq
+		if len(changes) < changeBatchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/austiecodes/gomor/internal/memory/store"
+)
+
+// TestMemoryWatcher checks that memories changed directly in the store are
+// listed, unlisted and announced to subscribers after a poll, and that
+// document chunks are never listed.
+func TestMemoryWatcher(t *testing.T) {
+	ctx := context.Background()
+	s, err := store.NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+	d := newDeps(s, filepath.Join(t.TempDir(), "settings.json"))
+
+	save := func(item *store.MemoryItem) {
+		t.Helper()
+		item.Source = store.SourceExplicit
+		if err := s.SaveMemory(item); err != nil {
+			t.Fatalf("save memory: %v", err)
+		}
+	}
+	save(&store.MemoryItem{ID: "existing", Text: "Prefers tabs"})
+	saveChunk := func(id string) {
+		t.Helper()
+		item := &store.MemoryItem{ID: id, Text: "A section of the conventions", Source: store.SourceDocument,
+			DocumentID: "doc", SourcePath: "/docs/conventions.md", ContentHash: id}
+		if err := s.SaveMemory(item); err != nil {
+			t.Fatalf("save chunk: %v", err)
+		}
+	}
+	saveChunk("chunk-1")
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "gomor-test"}, &mcp.ServerOptions{
+		SubscribeHandler:   handleSubscribe,
+		UnsubscribeHandler: handleUnsubscribe,
+	})
+	watcher, err := newMemoryWatcher(server, d)
+	if err != nil {
+		t.Fatalf("new watcher: %v", err)
+	}
+
+	updated := make(chan string, 10)
+	client := mcp.NewClient(&mcp.Implementation{Name: "client"}, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(ctx context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			updated <- req.Params.URI
+		},
+	})
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("connect server: %v", err)
+	}
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("connect client: %v", err)
+	}
+	defer session.Close()
+
+	listed := func() []string {
+		t.Helper()
+		res, err := session.ListResources(ctx, nil)
+		if err != nil {
+			t.Fatalf("list resources: %v", err)
+		}
+		var uris []string
+		for _, r := range res.Resources {
+			uris = append(uris, r.URI)
+		}
+		slices.Sort(uris)
+		return uris
+	}
+	if got := listed(); !slices.Equal(got, []string{"memory://existing"}) {
+		t.Fatalf("expected the existing memory to be listed, got %v", got)
+	}
+
+	if err := session.Subscribe(ctx, &mcp.SubscribeParams{URI: "memory://existing"}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	save(&store.MemoryItem{ID: "new", Text: "Uses Go"})
+	saveChunk("chunk-2")
+	if err := s.SetMemoryStatus("existing", store.StatusArchived); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	if err := watcher.poll(ctx); err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+
+	if got := listed(); !slices.Equal(got, []string{"memory://new"}) {
+		t.Fatalf("expected only the new memory to be listed, got %v", got)
+	}
+	select {
+	case uri := <-updated:
+		if uri != "memory://existing" {
+			t.Fatalf("unexpected update for %s", uri)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no update notification for the subscribed memory")
+	}
+}
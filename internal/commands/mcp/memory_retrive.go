@@ -7,8 +7,6 @@ import (
 
 	"github.com/austiecodes/gomor/internal/client"
 	"github.com/austiecodes/gomor/internal/memory/retrieval"
-	"github.com/austiecodes/gomor/internal/memory/store"
-	"github.com/austiecodes/gomor/internal/provider"
 	"github.com/austiecodes/gomor/internal/types"
 	"github.com/austiecodes/gomor/internal/utils"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -16,44 +14,79 @@ import (
 
 // MemoryRetrieveInput defines the input schema for the memory retrieve tool
 type MemoryRetrieveInput struct {
-	Query string `json:"query" jsonschema:"the query to search for related memories"`
+	Query         string  `json:"query" jsonschema:"the query to search for related memories; may include qualifiers like tag:go source:explicit after:2025-01-01 before:2025-06-01 limit:5 minscore:0.5"`
+	Tags          string  `json:"tags,omitempty" jsonschema:"comma-separated tags; only memories with all of these tags are returned"`
+	Source        string  `json:"source,omitempty" jsonschema:"only return memories from this source: explicit, extracted or document"`
+	CreatedAfter  string  `json:"created_after,omitempty" jsonschema:"only return memories created on or after this date (YYYY-MM-DD or RFC 3339)"`
+	CreatedBefore string  `json:"created_before,omitempty" jsonschema:"only return memories created before this date (YYYY-MM-DD or RFC 3339)"`
+	MinScore      float64 `json:"min_score,omitempty" jsonschema:"minimum relevance score between 0 and 1"`
+	TopK          int     `json:"top_k,omitempty" jsonschema:"maximum number of memories to return (defaults to the configured memory_top_k)"`
+	Explain       bool    `json:"explain,omitempty" jsonschema:"also return how the results were found: transformed queries, per-search hits, fusion terms and stage timings"`
 }
 
 // MemoryRetrieveOutput defines the output schema for the memory retrieve tool
 type MemoryRetrieveOutput struct {
-	Results string `json:"results" jsonschema:"formatted text containing retrieved memories"`
+	Results         string                    `json:"results" jsonschema:"formatted text containing retrieved memories"`
+	Degraded        bool                      `json:"degraded,omitempty" jsonschema:"true when a retrieval stage failed or ran out of time, so results may be incomplete"`
+	DegradedReasons []string                  `json:"degraded_reasons,omitempty" jsonschema:"which stages were degraded and why"`
+	Explain         *retrieval.RetrievalTrace `json:"explain,omitempty" jsonschema:"retrieval trace, present when explain was requested"`
 }
 
 // handleMemoryRetrieve handles the goa_memory_retrieve tool call (unified hybrid search)
-func handleMemoryRetrieve(ctx context.Context, request *mcp.CallToolRequest, input MemoryRetrieveInput) (*mcp.CallToolResult, MemoryRetrieveOutput, error) {
+func (d *deps) handleMemoryRetrieve(ctx context.Context, request *mcp.CallToolRequest, input MemoryRetrieveInput) (*mcp.CallToolResult, MemoryRetrieveOutput, error) {
 	// Validate query (required)
 	query := strings.TrimSpace(input.Query)
 	if query == "" {
 		return nil, MemoryRetrieveOutput{}, fmt.Errorf("parameter 'query' must be a non-empty string")
 	}
 
-	// Load config
-	config, err := utils.LoadConfig()
+	// Validate qualifiers and filters before doing any work
+	query, opts, err := parseRetrieveInput(input)
 	if err != nil {
-		return nil, MemoryRetrieveOutput{}, fmt.Errorf("failed to load config: %w", err)
+		return nil, MemoryRetrieveOutput{}, err
 	}
 
-	if config.Model.EmbeddingModel == nil {
-		return nil, MemoryRetrieveOutput{}, fmt.Errorf("embedding model not configured. Run 'gomor set' to configure")
+	config, err := d.Config()
+	if err != nil {
+		return nil, MemoryRetrieveOutput{}, err
+	}
+
+	ret, err := d.newRetriever(config)
+	if err != nil {
+		return nil, MemoryRetrieveOutput{}, err
 	}
 
-	// Open memory store
-	memStore, err := store.NewStore()
+	// Perform retrieval
+	response, err := ret.RetrieveWithOptions(ctx, query, opts)
 	if err != nil {
-		return nil, MemoryRetrieveOutput{}, fmt.Errorf("failed to open memory store: %w", err)
+		return nil, MemoryRetrieveOutput{}, fmt.Errorf("retrieval failed: %w", err)
+	}
+
+	// Format results
+	result := retrieval.FormatAsTextWithLimit(response, config.Memory.MaxInjectedChars)
+	if response.Explain != nil {
+		result += "\n" + retrieval.FormatTrace(response.Explain)
+	}
+	return nil, MemoryRetrieveOutput{
+		Results:         result,
+		Degraded:        response.Degraded,
+		DegradedReasons: response.DegradedReasons,
+		Explain:         response.Explain,
+	}, nil
+}
+
+// newRetriever creates a retriever over the store with the configured models.
+// The tool model is optional; without it queries are searched as given.
+func (d *deps) newRetriever(config *utils.Config) (*retrieval.Retriever, error) {
+	if config.Model.EmbeddingModel == nil {
+		return nil, fmt.Errorf("embedding model not configured. Run 'gomor set' to configure")
 	}
-	defer memStore.Close()
 
 	// Create embedding client
 	embeddingModel := *config.Model.EmbeddingModel
-	embClient, err := provider.NewEmbeddingClient(config, embeddingModel.Provider)
+	embClient, err := d.EmbeddingClient(embeddingModel.Provider)
 	if err != nil {
-		return nil, MemoryRetrieveOutput{}, fmt.Errorf("failed to create embedding client: %w", err)
+		return nil, err
 	}
 
 	// Create query client for LLM transformations (optional, may be nil)
@@ -61,28 +94,74 @@ func handleMemoryRetrieve(ctx context.Context, request *mcp.CallToolRequest, inp
 	toolModel := types.Model{}
 	if config.Model.ToolModel != nil {
 		toolModel = *config.Model.ToolModel
-		queryClient, _ = provider.NewQueryClient(config, toolModel.Provider)
+		queryClient, _ = d.QueryClient(toolModel.Provider)
 	}
 
 	// Create retriever
 	ret := retrieval.NewRetriever(
-		memStore,
+		d.store,
 		embClient,
 		queryClient,
 		embeddingModel,
 		toolModel,
 		config.Memory,
 	)
+	ret.SetEmbeddingClientFactory(d.EmbeddingClient)
+	return ret, nil
+}
 
-	// Perform retrieval
-	response, err := ret.Retrieve(ctx, query)
+// parseRetrieveInput extracts inline qualifiers (tag:go, after:2025-01-01, ...)
+// from the query and combines them with the explicit filter parameters.
+// Explicit tags are added to inline ones; other explicit parameters win.
+func parseRetrieveInput(input MemoryRetrieveInput) (string, retrieval.RetrieveOptions, error) {
+	parsed, err := retrieval.ParseQuery(input.Query)
 	if err != nil {
-		return nil, MemoryRetrieveOutput{}, fmt.Errorf("retrieval failed: %w", err)
+		return "", retrieval.RetrieveOptions{}, fmt.Errorf("parameter 'query': %w", err)
 	}
+	if parsed.Text == "" {
+		return "", retrieval.RetrieveOptions{}, fmt.Errorf("parameter 'query' must contain search text besides qualifiers")
+	}
+	opts := parsed.Options
 
-	// Format results
-	result := retrieval.FormatAsText(response)
-	return nil, MemoryRetrieveOutput{
-		Results: result,
-	}, nil
+	opts.Filter.Tags = append(opts.Filter.Tags, splitTags(input.Tags)...)
+
+	source, err := retrieval.ParseSource(input.Source)
+	if err != nil {
+		return "", opts, fmt.Errorf("parameter 'source': %w", err)
+	}
+	if source != "" {
+		opts.Filter.Source = source
+	}
+
+	if strings.TrimSpace(input.CreatedAfter) != "" {
+		if opts.Filter.CreatedAfter, err = retrieval.ParseDate(input.CreatedAfter); err != nil {
+			return "", opts, fmt.Errorf("parameter 'created_after': %w", err)
+		}
+	}
+	if strings.TrimSpace(input.CreatedBefore) != "" {
+		if opts.Filter.CreatedBefore, err = retrieval.ParseDate(input.CreatedBefore); err != nil {
+			return "", opts, fmt.Errorf("parameter 'created_before': %w", err)
+		}
+	}
+	if !opts.Filter.CreatedAfter.IsZero() && !opts.Filter.CreatedBefore.IsZero() &&
+		!opts.Filter.CreatedAfter.Before(opts.Filter.CreatedBefore) {
+		return "", opts, fmt.Errorf("parameter 'created_after' must be earlier than 'created_before'")
+	}
+
+	if input.MinScore < 0 || input.MinScore > 1 {
+		return "", opts, fmt.Errorf("parameter 'min_score' must be between 0 and 1")
+	}
+	if input.MinScore > 0 {
+		opts.MinScore = input.MinScore
+	}
+
+	if input.TopK < 0 {
+		return "", opts, fmt.Errorf("parameter 'top_k' must not be negative")
+	}
+	if input.TopK > 0 {
+		opts.TopK = input.TopK
+	}
+	opts.Explain = input.Explain
+
+	return parsed.Text, opts, nil
 }
@@ -5,93 +5,107 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/austiecodes/gomor/internal/memory/profile"
 	"github.com/austiecodes/gomor/internal/memory/store"
-	"github.com/austiecodes/gomor/internal/provider"
+	"github.com/austiecodes/gomor/internal/types"
 	"github.com/austiecodes/gomor/internal/utils"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 // MemorySaveInput defines the input schema for the memory save tool
 type MemorySaveInput struct {
-	Text string `json:"text" jsonschema:"the preference or fact to save"`
-	Tags string `json:"tags,omitempty" jsonschema:"comma-separated tags for categorization"`
+	Text       string  `json:"text" jsonschema:"the preference or fact to save"`
+	Tags       string  `json:"tags,omitempty" jsonschema:"comma-separated tags for categorization"`
+	Pinned     bool    `json:"pinned,omitempty" jsonschema:"always include this memory in the user profile"`
+	Importance float64 `json:"importance,omitempty" jsonschema:"how important the memory is, from 0 to 1; important memories are included in the user profile"`
 }
 
 // MemorySaveOutput defines the output schema for the memory save tool
 type MemorySaveOutput struct {
-	Message string `json:"message" jsonschema:"success message with memory ID"`
-	ID      string `json:"id" jsonschema:"the ID of the saved memory"`
+	Message   string `json:"message" jsonschema:"success message with memory ID"`
+	ID        string `json:"id" jsonschema:"the ID of the saved memory"`
+	InProfile bool   `json:"in_profile,omitempty" jsonschema:"whether the memory is included in the user profile resource"`
 }
 
 // handleMemorySave handles the memory_save tool call
-func handleMemorySave(ctx context.Context, request *mcp.CallToolRequest, input MemorySaveInput) (*mcp.CallToolResult, MemorySaveOutput, error) {
+func (d *deps) handleMemorySave(ctx context.Context, request *mcp.CallToolRequest, input MemorySaveInput) (*mcp.CallToolResult, MemorySaveOutput, error) {
 	// Validate text (required)
 	text := strings.TrimSpace(input.Text)
 	if text == "" {
 		return nil, MemorySaveOutput{}, fmt.Errorf("parameter 'text' must be a non-empty string")
 	}
 
+	if input.Importance < 0 || input.Importance > 1 {
+		return nil, MemorySaveOutput{}, fmt.Errorf("parameter 'importance' must be between 0 and 1")
+	}
+
 	// Extract tags (optional)
-	var tags []string
-	if input.Tags != "" {
-		for _, t := range strings.Split(input.Tags, ",") {
-			t = strings.TrimSpace(t)
-			if t != "" {
-				tags = append(tags, t)
-			}
-		}
+	tags := splitTags(input.Tags)
+
+	config, err := d.Config()
+	if err != nil {
+		return nil, MemorySaveOutput{}, err
 	}
 
-	// Load config for embedding
-	config, err := utils.LoadConfig()
+	embeddingModel, embedding, err := d.embedText(ctx, config, text)
 	if err != nil {
-		return nil, MemorySaveOutput{}, fmt.Errorf("failed to load config: %w", err)
+		return nil, MemorySaveOutput{}, err
+	}
+
+	// Save memory
+	item := &store.MemoryItem{
+		Text:       text,
+		Tags:       tags,
+		Source:     store.SourceExplicit,
+		Pinned:     input.Pinned,
+		Importance: input.Importance,
+		Provider:   embeddingModel.Provider,
+		ModelID:    embeddingModel.ModelID,
+		Dim:        len(embedding),
+		Embedding:  embedding,
+	}
+
+	if err := d.store.SaveMemory(item); err != nil {
+		return nil, MemorySaveOutput{}, fmt.Errorf("failed to save memory: %w", err)
 	}
 
+	return nil, MemorySaveOutput{
+		Message:   fmt.Sprintf("Memory saved successfully (id: %s)", item.ID),
+		ID:        item.ID,
+		InProfile: profile.Qualifies(*item, config.Memory),
+	}, nil
+}
+
+// embedText embeds the text of a memory with the configured embedding model,
+// normalized for cosine similarity.
+func (d *deps) embedText(ctx context.Context, config *utils.Config, text string) (types.Model, []float32, error) {
 	if config.Model.EmbeddingModel == nil {
-		return nil, MemorySaveOutput{}, fmt.Errorf("embedding model not configured. Run 'gomor set' to configure")
+		return types.Model{}, nil, fmt.Errorf("embedding model not configured. Run 'gomor set' to configure")
 	}
 
 	// Create embedding client
 	embeddingModel := *config.Model.EmbeddingModel
-	embClient, err := provider.NewEmbeddingClient(config, embeddingModel.Provider)
+	embClient, err := d.EmbeddingClient(embeddingModel.Provider)
 	if err != nil {
-		return nil, MemorySaveOutput{}, fmt.Errorf("failed to create embedding client: %w", err)
+		return types.Model{}, nil, err
 	}
 
 	// Generate embedding
 	embedding, err := embClient.Embed(ctx, embeddingModel, text)
 	if err != nil {
-		return nil, MemorySaveOutput{}, fmt.Errorf("failed to generate embedding: %w", err)
-	}
-
-	// Normalize embedding for cosine similarity
-	normalizedEmbedding := store.NormalizeVector(embedding)
-
-	// Open memory store
-	memStore, err := store.NewStore()
-	if err != nil {
-		return nil, MemorySaveOutput{}, fmt.Errorf("failed to open memory store: %w", err)
-	}
-	defer memStore.Close()
-
-	// Save memory
-	item := &store.MemoryItem{
-		Text:      text,
-		Tags:      tags,
-		Source:    store.SourceExplicit,
-		Provider:  embeddingModel.Provider,
-		ModelID:   embeddingModel.ModelID,
-		Dim:       len(normalizedEmbedding),
-		Embedding: normalizedEmbedding,
+		return types.Model{}, nil, fmt.Errorf("failed to generate embedding: %w", err)
 	}
+	return embeddingModel, store.NormalizeVector(embedding), nil
+}
 
-	if err := memStore.SaveMemory(item); err != nil {
-		return nil, MemorySaveOutput{}, fmt.Errorf("failed to save memory: %w", err)
+// splitTags parses a comma-separated tag list, dropping empty entries.
+func splitTags(s string) []string {
+	var tags []string
+	for _, t := range strings.Split(s, ",") {
+		t = strings.TrimSpace(t)
+		if t != "" {
+			tags = append(tags, t)
+		}
 	}
-
-	return nil, MemorySaveOutput{
-		Message: fmt.Sprintf("Memory saved successfully (id: %s)", item.ID),
-		ID:      item.ID,
-	}, nil
+	return tags
 }
@@ -2,20 +2,24 @@ package mcp
 
 import (
 	"context"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/austiecodes/gomor/internal/memory/store"
 )
 
 // TestHandleMemorySave_EmptyText tests that empty text returns an error
 func TestHandleMemorySave_EmptyText(t *testing.T) {
+	d := newTestDeps(t)
 	ctx := context.Background()
 	request := &mcp.CallToolRequest{}
 
 	// Test empty text
 	input := MemorySaveInput{Text: ""}
-	_, _, err := handleMemorySave(ctx, request, input)
+	_, _, err := d.handleMemorySave(ctx, request, input)
 	if err == nil {
 		t.Fatal("expected error for empty text, got nil")
 	}
@@ -25,14 +29,30 @@ func TestHandleMemorySave_EmptyText(t *testing.T) {
 
 	// Test whitespace-only text
 	input = MemorySaveInput{Text: "   "}
-	_, _, err = handleMemorySave(ctx, request, input)
+	_, _, err = d.handleMemorySave(ctx, request, input)
 	if err == nil {
 		t.Fatal("expected error for whitespace-only text, got nil")
 	}
 }
 
+// TestHandleMemorySave_InvalidImportance tests that importance outside 0-1 returns an error
+func TestHandleMemorySave_InvalidImportance(t *testing.T) {
+	d := newTestDeps(t)
+	ctx := context.Background()
+	request := &mcp.CallToolRequest{}
+
+	for _, importance := range []float64{-0.1, 1.5} {
+		input := MemorySaveInput{Text: "Prefers tabs", Importance: importance}
+		_, _, err := d.handleMemorySave(ctx, request, input)
+		if err == nil || !strings.Contains(err.Error(), "importance") {
+			t.Fatalf("expected importance error for %g, got %v", importance, err)
+		}
+	}
+}
+
 // TestHandleMemorySave_Success tests successful memory saving
 func TestHandleMemorySave_Success(t *testing.T) {
+	d := newTestDeps(t)
 	ctx := context.Background()
 	request := &mcp.CallToolRequest{}
 
@@ -41,7 +61,7 @@ func TestHandleMemorySave_Success(t *testing.T) {
 		Tags: "test, unit-test",
 	}
 
-	_, output, err := handleMemorySave(ctx, request, input)
+	_, output, err := d.handleMemorySave(ctx, request, input)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -58,12 +78,13 @@ func TestHandleMemorySave_Success(t *testing.T) {
 
 // TestHandleMemoryRetrieve_EmptyQuery tests that empty query returns an error
 func TestHandleMemoryRetrieve_EmptyQuery(t *testing.T) {
+	d := newTestDeps(t)
 	ctx := context.Background()
 	request := &mcp.CallToolRequest{}
 
 	// Test empty query
 	input := MemoryRetrieveInput{Query: ""}
-	_, _, err := handleMemoryRetrieve(ctx, request, input)
+	_, _, err := d.handleMemoryRetrieve(ctx, request, input)
 	if err == nil {
 		t.Fatal("expected error for empty query, got nil")
 	}
@@ -73,7 +94,7 @@ func TestHandleMemoryRetrieve_EmptyQuery(t *testing.T) {
 
 	// Test whitespace-only query
 	input = MemoryRetrieveInput{Query: "   "}
-	_, _, err = handleMemoryRetrieve(ctx, request, input)
+	_, _, err = d.handleMemoryRetrieve(ctx, request, input)
 	if err == nil {
 		t.Fatal("expected error for whitespace-only query, got nil")
 	}
@@ -81,6 +102,7 @@ func TestHandleMemoryRetrieve_EmptyQuery(t *testing.T) {
 
 // TestHandleMemoryRetrieve_Success tests successful memory retrieval
 func TestHandleMemoryRetrieve_Success(t *testing.T) {
+	d := newTestDeps(t)
 	ctx := context.Background()
 	request := &mcp.CallToolRequest{}
 
@@ -89,7 +111,7 @@ func TestHandleMemoryRetrieve_Success(t *testing.T) {
 		Text: "Go programming language was created by Google in 2009",
 		Tags: "go, programming, google",
 	}
-	_, saveOutput, err := handleMemorySave(ctx, request, saveInput)
+	_, saveOutput, err := d.handleMemorySave(ctx, request, saveInput)
 	if err != nil {
 		t.Fatalf("failed to save test memory: %v", err)
 	}
@@ -97,7 +119,7 @@ func TestHandleMemoryRetrieve_Success(t *testing.T) {
 
 	// Now retrieve it
 	retrieveInput := MemoryRetrieveInput{Query: "Go programming language Google"}
-	_, retrieveOutput, err := handleMemoryRetrieve(ctx, request, retrieveInput)
+	_, retrieveOutput, err := d.handleMemoryRetrieve(ctx, request, retrieveInput)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -112,6 +134,7 @@ func TestHandleMemoryRetrieve_Success(t *testing.T) {
 
 // TestHandleMemorySave_TagsParsing tests tag parsing logic
 func TestHandleMemorySave_TagsParsing(t *testing.T) {
+	d := newTestDeps(t)
 	ctx := context.Background()
 	request := &mcp.CallToolRequest{}
 
@@ -133,7 +156,7 @@ func TestHandleMemorySave_TagsParsing(t *testing.T) {
 				Text: "Test memory: " + tc.name,
 				Tags: tc.tags,
 			}
-			_, output, err := handleMemorySave(ctx, request, input)
+			_, output, err := d.handleMemorySave(ctx, request, input)
 			if tc.wantSave {
 				if err != nil {
 					t.Fatalf("unexpected error: %v", err)
@@ -150,3 +173,167 @@ func TestHandleMemorySave_TagsParsing(t *testing.T) {
 		})
 	}
 }
+
+// TestParseRetrieveInput tests parsing of the memory_retrieve query qualifiers and filters
+func TestParseRetrieveInput(t *testing.T) {
+	query, opts, err := parseRetrieveInput(MemoryRetrieveInput{
+		Query:         "tag:go error handling limit:5",
+		Tags:          " editor, ,",
+		Source:        "Extracted",
+		CreatedAfter:  "2025-01-01",
+		CreatedBefore: "2025-02-01T00:00:00Z",
+		MinScore:      0.5,
+		TopK:          3,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query != "error handling" {
+		t.Fatalf("unexpected query text: %q", query)
+	}
+	if len(opts.Filter.Tags) != 2 || opts.Filter.Tags[0] != "go" || opts.Filter.Tags[1] != "editor" {
+		t.Fatalf("unexpected tags: %v", opts.Filter.Tags)
+	}
+	if opts.Filter.Source != "extracted" || opts.MinScore != 0.5 || opts.TopK != 3 {
+		t.Fatalf("unexpected options: %+v", opts)
+	}
+	if opts.Filter.CreatedAfter.IsZero() || opts.Filter.CreatedBefore.IsZero() {
+		t.Fatalf("expected both dates to be set: %+v", opts.Filter)
+	}
+
+	invalid := []MemoryRetrieveInput{
+		{Query: "q", Source: "imported"},
+		{Query: "q", CreatedAfter: "last week"},
+		{Query: "q", CreatedAfter: "2025-03-01", CreatedBefore: "2025-02-01"},
+		{Query: "q", MinScore: 1.5},
+		{Query: "q", TopK: -1},
+		{Query: "tag:go"},
+	}
+	for _, input := range invalid {
+		if _, _, err := parseRetrieveInput(input); err == nil {
+			t.Errorf("expected error for %+v", input)
+		}
+	}
+}
+
+// TestParseRetrieveInput_ColonText tests that words ending in a colon which
+// aren't qualifiers stay in the query text
+func TestParseRetrieveInput_ColonText(t *testing.T) {
+	for _, q := range []string{"error: nil pointer in handler", "lang:go errors"} {
+		query, opts, err := parseRetrieveInput(MemoryRetrieveInput{Query: q})
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", q, err)
+		}
+		if query != q {
+			t.Fatalf("%q: unexpected query text: %q", q, query)
+		}
+		if len(opts.Filter.Tags) != 0 || opts.Filter.Source != "" {
+			t.Fatalf("%q: unexpected filter: %+v", q, opts.Filter)
+		}
+	}
+}
+
+// TestHandleMemoryList_InvalidInput tests that bad filters and paging return an error
+func TestHandleMemoryList_InvalidInput(t *testing.T) {
+	d := newTestDeps(t)
+	ctx := context.Background()
+	request := &mcp.CallToolRequest{}
+
+	cases := map[string]MemoryListInput{
+		"source":        {Source: "chat"},
+		"status":        {Status: "deleted"},
+		"created_after": {CreatedAfter: "yesterday"},
+		"limit":         {Limit: maxListLimit + 1},
+		"offset":        {Offset: -1},
+	}
+	for param, input := range cases {
+		_, _, err := d.handleMemoryList(ctx, request, input)
+		if err == nil || !strings.Contains(err.Error(), param) {
+			t.Fatalf("expected %s error, got %v", param, err)
+		}
+	}
+}
+
+// TestHandleMemoryUpdate_InvalidInput tests that updates without an ID or changes return an error
+func TestHandleMemoryUpdate_InvalidInput(t *testing.T) {
+	d := newTestDeps(t)
+	ctx := context.Background()
+	request := &mcp.CallToolRequest{}
+
+	empty, importance := "  ", 2.0
+	cases := map[string]MemoryUpdateInput{
+		"id":         {Text: &empty},
+		"nothing":    {ID: "abc"},
+		"text":       {ID: "abc", Text: &empty},
+		"importance": {ID: "abc", Importance: &importance},
+	}
+	for want, input := range cases {
+		_, _, err := d.handleMemoryUpdate(ctx, request, input)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %s error, got %v", want, err)
+		}
+	}
+}
+
+// TestHandleMemoryDelete_EmptyID tests that an empty ID returns an error
+func TestHandleMemoryDelete_EmptyID(t *testing.T) {
+	d := newTestDeps(t)
+	_, _, err := d.handleMemoryDelete(context.Background(), &mcp.CallToolRequest{}, MemoryDeleteInput{ID: " "})
+	if err == nil || !strings.Contains(err.Error(), "non-empty string") {
+		t.Fatalf("expected error for empty id, got %v", err)
+	}
+}
+
+// TestHandleMemoryForget_EmptyQuery tests that an empty query returns an error
+func TestHandleMemoryForget_EmptyQuery(t *testing.T) {
+	d := newTestDeps(t)
+	input := MemoryForgetInput{Query: "  ", ConfirmIDs: []string{"abc"}}
+	_, _, err := d.handleMemoryForget(context.Background(), &mcp.CallToolRequest{}, input)
+	if err == nil || !strings.Contains(err.Error(), "non-empty string") {
+		t.Fatalf("expected error for empty query, got %v", err)
+	}
+}
+
+// TestHandleMemoryForget_ConfirmIDs tests that only confirmed IDs among the
+// candidates of the confirmation token are deleted, and the token works once
+func TestHandleMemoryForget_ConfirmIDs(t *testing.T) {
+	d := newTestDeps(t)
+	ctx := context.Background()
+	request := &mcp.CallToolRequest{}
+
+	for _, id := range []string{"job", "team", "editor"} {
+		if err := d.store.SaveMemory(&store.MemoryItem{ID: id, Text: "memory " + id, Source: store.SourceExplicit}); err != nil {
+			t.Fatalf("save memory: %v", err)
+		}
+	}
+
+	input := MemoryForgetInput{Query: "my old job", ConfirmIDs: []string{"job", "editor"}}
+	if _, _, err := d.handleMemoryForget(ctx, request, input); err == nil || !strings.Contains(err.Error(), "confirmation_token") {
+		t.Fatalf("expected confirmation_token error without a token, got %v", err)
+	}
+
+	input.Token = d.saveForgetRequest("my old job", []string{"job", "team"})
+	_, output, err := d.handleMemoryForget(ctx, request, input)
+	if err != nil {
+		t.Fatalf("forget: %v", err)
+	}
+	if !slices.Equal(output.Deleted, []string{"job"}) || !slices.Equal(output.Rejected, []string{"editor"}) {
+		t.Fatalf("got deleted %v, rejected %v", output.Deleted, output.Rejected)
+	}
+	if m, _ := d.store.GetMemory("editor"); m == nil {
+		t.Fatal("expected editor, which was not a candidate, to be kept")
+	}
+
+	entries, err := d.store.GetAuditLog(10)
+	if err != nil {
+		t.Fatalf("audit log: %v", err)
+	}
+	if len(entries) != 1 || entries[0].MemoryID != "job" || entries[0].Reason != "my old job" {
+		t.Fatalf("unexpected audit log %+v", entries)
+	}
+
+	input.ConfirmIDs = []string{"team"}
+	if _, _, err := d.handleMemoryForget(ctx, request, input); err == nil {
+		t.Fatal("expected the used token to be rejected")
+	}
+}
@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/austiecodes/gomor/internal/memory/profile"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MemoryUpdateInput defines the input schema for the memory update tool.
+// Parameters left out are not changed.
+type MemoryUpdateInput struct {
+	ID         string   `json:"id" jsonschema:"the ID of the memory to update"`
+	Text       *string  `json:"text,omitempty" jsonschema:"the corrected text; the memory is re-embedded"`
+	Tags       *string  `json:"tags,omitempty" jsonschema:"comma-separated tags replacing the current ones; empty removes all tags"`
+	Pinned     *bool    `json:"pinned,omitempty" jsonschema:"always include this memory in the user profile"`
+	Importance *float64 `json:"importance,omitempty" jsonschema:"how important the memory is, from 0 to 1; 0 clears it"`
+}
+
+// MemoryUpdateOutput defines the output schema for the memory update tool
+type MemoryUpdateOutput struct {
+	Message   string           `json:"message" jsonschema:"what was updated"`
+	Memory    store.MemoryItem `json:"memory" jsonschema:"the memory after the update"`
+	InProfile bool             `json:"in_profile,omitempty" jsonschema:"whether the memory is included in the user profile resource"`
+}
+
+// handleMemoryUpdate handles the memory_update tool call
+func (d *deps) handleMemoryUpdate(ctx context.Context, request *mcp.CallToolRequest, input MemoryUpdateInput) (*mcp.CallToolResult, MemoryUpdateOutput, error) {
+	id := strings.TrimSpace(input.ID)
+	if id == "" {
+		return nil, MemoryUpdateOutput{}, fmt.Errorf("parameter 'id' must be a non-empty string")
+	}
+	if input.Text == nil && input.Tags == nil && input.Pinned == nil && input.Importance == nil {
+		return nil, MemoryUpdateOutput{}, fmt.Errorf("nothing to update: give text, tags, pinned or importance")
+	}
+	var text string
+	if input.Text != nil {
+		text = strings.TrimSpace(*input.Text)
+		if text == "" {
+			return nil, MemoryUpdateOutput{}, fmt.Errorf("parameter 'text' must be a non-empty string")
+		}
+	}
+	if input.Importance != nil && (*input.Importance < 0 || *input.Importance > 1) {
+		return nil, MemoryUpdateOutput{}, fmt.Errorf("parameter 'importance' must be between 0 and 1")
+	}
+
+	config, err := d.Config()
+	if err != nil {
+		return nil, MemoryUpdateOutput{}, err
+	}
+
+	memory, err := d.store.GetMemory(id)
+	if err != nil {
+		return nil, MemoryUpdateOutput{}, err
+	}
+	if memory == nil {
+		return nil, MemoryUpdateOutput{}, fmt.Errorf("memory %s not found", id)
+	}
+
+	// Document chunks are rewritten on every ingest, so edits would be lost
+	if (input.Text != nil || input.Tags != nil) && memory.DocumentID != "" {
+		return nil, MemoryUpdateOutput{}, fmt.Errorf("memory %s is a chunk of %s; edit the file and run 'gomor ingest' instead", id, memory.SourcePath)
+	}
+
+	var changed []string
+	if input.Text != nil && text != memory.Text {
+		embeddingModel, embedding, err := d.embedText(ctx, config, text)
+		if err != nil {
+			return nil, MemoryUpdateOutput{}, err
+		}
+		memory.Text = text
+		memory.Provider = embeddingModel.Provider
+		memory.ModelID = embeddingModel.ModelID
+		memory.Dim = len(embedding)
+		memory.Embedding = embedding
+		changed = append(changed, "text")
+	}
+	if input.Tags != nil {
+		memory.Tags = splitTags(*input.Tags)
+		changed = append(changed, "tags")
+	}
+	if len(changed) > 0 {
+		if err := d.store.UpdateMemory(memory); err != nil {
+			return nil, MemoryUpdateOutput{}, err
+		}
+	}
+	if input.Pinned != nil {
+		if err := d.store.SetMemoryPinned(id, *input.Pinned); err != nil {
+			return nil, MemoryUpdateOutput{}, err
+		}
+		memory.Pinned = *input.Pinned
+		changed = append(changed, "pinned")
+	}
+	if input.Importance != nil {
+		if err := d.store.SetMemoryImportance(id, *input.Importance); err != nil {
+			return nil, MemoryUpdateOutput{}, err
+		}
+		memory.Importance = *input.Importance
+		changed = append(changed, "importance")
+	}
+
+	message := fmt.Sprintf("Memory %s unchanged", id)
+	if len(changed) > 0 {
+		message = fmt.Sprintf("Memory %s updated (%s)", id, strings.Join(changed, ", "))
+	}
+	return nil, MemoryUpdateOutput{
+		Message:   message,
+		Memory:    *memory,
+		InProfile: profile.Qualifies(*memory, config.Memory),
+	}, nil
+}
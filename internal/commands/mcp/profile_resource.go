@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/memory/profile"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Regenerating the profile is bounded: reads wait up to profileReadTimeout
+// before serving the stored version, while the memory watcher, which
+// regenerates it in the background, waits up to profileRefreshTimeout.
+const (
+	profileReadTimeout    = 5 * time.Second
+	profileRefreshTimeout = 2 * time.Minute
+)
+
+// handleProfileRead handles reads of the profile resource. The profile is
+// regenerated first if the memories it is written from changed and that
+// finishes within profileReadTimeout; otherwise the latest stored version is
+// returned if there is one, and the memory watcher writes the new one.
+func (d *deps) handleProfileRead(ctx context.Context, request *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	if request.Params.URI != profile.URI {
+		return nil, mcp.ResourceNotFoundError(request.Params.URI)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, profileReadTimeout)
+	defer cancel()
+	current, _, err := d.refreshProfile(ctx)
+	if err != nil {
+		stored, getErr := d.store.GetProfile()
+		if getErr != nil || stored == nil {
+			return nil, err
+		}
+		current = stored
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{
+			URI:      profile.URI,
+			MIMEType: "text/markdown",
+			Text:     current.Content,
+		}},
+	}, nil
+}
+
+// refreshProfile writes a new version of the profile if the memories it is
+// written from changed, and returns the latest version.
+func (d *deps) refreshProfile(ctx context.Context) (*store.Profile, bool, error) {
+	config, err := d.Config()
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The profile can still be read without a tool model, just not regenerated
+	var queryClient client.QueryClient
+	var toolModel types.Model
+	if config.Model.ToolModel != nil {
+		toolModel = *config.Model.ToolModel
+		queryClient, err = d.QueryClient(toolModel.Provider)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	return profile.NewGenerator(d.store, queryClient, toolModel, config.Memory).Refresh(ctx)
+}
+
+// handleSubscribe accepts subscriptions to the profile and memory resources;
+// the server keeps track of subscribers.
+func handleSubscribe(ctx context.Context, request *mcp.SubscribeRequest) error {
+	uri := request.Params.URI
+	if uri != profile.URI && (!strings.HasPrefix(uri, memoryURIPrefix) || uri == memoryURIPrefix) {
+		return mcp.ResourceNotFoundError(uri)
+	}
+	return nil
+}
+
+// handleUnsubscribe accepts unsubscribing from any resource.
+func handleUnsubscribe(ctx context.Context, request *mcp.UnsubscribeRequest) error {
+	return nil
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/memory/profile"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/types"
+)
+
+// profileStream streams a single canned response.
+type profileStream struct {
+	text string
+	done bool
+}
+
+func (s *profileStream) Next() bool {
+	if s.done {
+		return false
+	}
+	s.done = true
+	return true
+}
+
+func (s *profileStream) GetChunk() string { return s.text }
+func (s *profileStream) Err() error       { return nil }
+func (s *profileStream) Close() error     { return nil }
+
+// slowProfileClient writes a fresh profile after delay, unless ctx is done first.
+type slowProfileClient struct {
+	delay time.Duration
+}
+
+func (c *slowProfileClient) ChatStream(ctx context.Context, model types.Model, query string) (client.StreamResponse, error) {
+	select {
+	case <-time.After(c.delay):
+		return &profileStream{text: "# User profile\n\nfresh"}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *slowProfileClient) ChatStreamWithContext(ctx context.Context, model types.Model, systemContext, query string) (client.StreamResponse, error) {
+	return c.ChatStream(ctx, model, query)
+}
+
+func (c *slowProfileClient) ListModels(ctx context.Context) ([]string, error) { return nil, nil }
+
+// newProfileDeps returns deps whose tool model is qc, over a store with a
+// stored profile that a pinned memory has made stale.
+func newProfileDeps(t *testing.T, qc client.QueryClient) *deps {
+	t.Helper()
+	s, err := store.NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	d := newDeps(s, filepath.Join(t.TempDir(), "settings.json"))
+	config, err := d.Config()
+	if err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	d.queryClients[config.Model.ToolModel.Provider] = qc
+
+	if _, err := s.SaveProfile("# User profile\n\nstored\n", nil, nil, 0); err != nil {
+		t.Fatalf("save profile: %v", err)
+	}
+	if err := s.SaveMemory(&store.MemoryItem{Text: "Works on gomor", Source: store.SourceExplicit, Pinned: true}); err != nil {
+		t.Fatalf("save memory: %v", err)
+	}
+	return d
+}
+
+// TestHandleProfileRead_SlowToolModel tests that a read doesn't wait for a
+// slow tool model but serves the stored profile
+func TestHandleProfileRead_SlowToolModel(t *testing.T) {
+	d := newProfileDeps(t, &slowProfileClient{delay: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	res, err := d.handleProfileRead(ctx, &mcp.ReadResourceRequest{Params: &mcp.ReadResourceParams{URI: profile.URI}})
+	if err != nil {
+		t.Fatalf("read profile: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("read took %v", elapsed)
+	}
+	if got := res.Contents[0].Text; !strings.Contains(got, "stored") {
+		t.Fatalf("expected the stored profile, got %q", got)
+	}
+}
+
+// TestMemoryWatcher_RefreshProfile tests that the watcher writes the new
+// profile version in the background
+func TestMemoryWatcher_RefreshProfile(t *testing.T) {
+	d := newProfileDeps(t, &slowProfileClient{})
+	w := &memoryWatcher{server: mcp.NewServer(&mcp.Implementation{Name: "gomor-test"}, nil), deps: d}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w.refreshProfile(ctx)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		current, err := d.store.GetProfile()
+		if err != nil {
+			t.Fatalf("get profile: %v", err)
+		}
+		if current.Version == 2 && strings.Contains(current.Content, "fresh") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the watcher to write version 2, got %+v", current)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
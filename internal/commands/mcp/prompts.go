@@ -0,0 +1,181 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/austiecodes/gomor/internal/memory/retrieval"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxCompletions is the most values a completion may return, per the MCP spec.
+const maxCompletions = 100
+
+// promptScopes are the values of the scope argument: the memory sources.
+var promptScopes = []string{
+	string(store.SourceExplicit),
+	string(store.SourceExtracted),
+	string(store.SourceDocument),
+}
+
+// recallContextPrompt pre-fills a conversation with the memories relevant to a topic.
+var recallContextPrompt = &mcp.Prompt{
+	Name:        "recall_context",
+	Title:       "Recall context",
+	Description: "Start a conversation about a topic with the memories relevant to it.",
+	Arguments: []*mcp.PromptArgument{
+		{Name: "topic", Description: "what you are about to work on", Required: true},
+		{Name: "tags", Description: "comma-separated tags; only memories with all of them are recalled"},
+		{Name: "scope", Description: "only recall memories from this source: explicit, extracted or document"},
+	},
+}
+
+// rememberSessionPrompt asks the model to save what it learned in the conversation.
+var rememberSessionPrompt = &mcp.Prompt{
+	Name:        "remember_session",
+	Title:       "Remember this session",
+	Description: "Ask the assistant to save the durable facts and preferences from this conversation to memory.",
+	Arguments: []*mcp.PromptArgument{
+		{Name: "topic", Description: "only remember what relates to this topic"},
+		{Name: "tags", Description: "comma-separated tags to add to every saved memory"},
+	},
+}
+
+// handleRecallContext handles the recall_context prompt
+func (d *deps) handleRecallContext(ctx context.Context, request *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	args := request.Params.Arguments
+	topic := strings.TrimSpace(args["topic"])
+	if topic == "" {
+		return nil, fmt.Errorf("argument 'topic' must be a non-empty string")
+	}
+
+	// The topic is searched like a memory_retrieve query, so it may carry qualifiers too
+	query, opts, err := parseRetrieveInput(MemoryRetrieveInput{Query: topic, Tags: args["tags"], Source: args["scope"]})
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := d.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := d.newRetriever(config)
+	if err != nil {
+		return nil, err
+	}
+	response, err := ret.RetrieveWithOptions(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("retrieval failed: %w", err)
+	}
+
+	text := fmt.Sprintf(`I'm about to work on: %s
+
+Here is what you remember about me that may be relevant. Use it as context and prefer it over assumptions; if something looks outdated, ask me before relying on it.
+
+%s`, topic, retrieval.FormatAsTextWithLimit(response, config.Memory.MaxInjectedChars))
+
+	return &mcp.GetPromptResult{
+		Description: fmt.Sprintf("Memories relevant to %s", topic),
+		Messages: []*mcp.PromptMessage{
+			{Role: "user", Content: &mcp.TextContent{Text: text}},
+		},
+	}, nil
+}
+
+// handleRememberSession handles the remember_session prompt
+func handleRememberSession(ctx context.Context, request *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	args := request.Params.Arguments
+	topic := strings.TrimSpace(args["topic"])
+	tags := splitTags(args["tags"])
+
+	var sb strings.Builder
+	sb.WriteString("Review our conversation so far and save what is worth remembering about me for future conversations.\n\n")
+	if topic != "" {
+		sb.WriteString(fmt.Sprintf("Only consider what relates to: %s\n\n", topic))
+	}
+	sb.WriteString(`- Save durable facts only: my preferences, the tools and conventions I use, my role and projects, and standing instructions. Skip one-off requests, temporary state, and anything I didn't confirm.
+- Write each memory as one short standalone statement about me, e.g. "Prefers tabs over spaces in Go code", and save it with memory_save.
+- Before saving, check with memory_retrieve that it isn't already remembered.
+- Set pinned only for things I asked you to always keep in mind, and an importance from 0 to 1 for how much the memory should shape future answers.
+`)
+	if len(tags) > 0 {
+		sb.WriteString(fmt.Sprintf("- Add these tags to every memory: %s\n", strings.Join(tags, ", ")))
+	} else {
+		sb.WriteString("- Add a few lowercase tags to each memory, reusing existing tags where they fit.\n")
+	}
+	sb.WriteString("\nWhen you are done, list what you saved.")
+
+	return &mcp.GetPromptResult{
+		Description: "Save the durable facts from this conversation",
+		Messages: []*mcp.PromptMessage{
+			{Role: "user", Content: &mcp.TextContent{Text: sb.String()}},
+		},
+	}, nil
+}
+
+// handleComplete completes prompt arguments: tags from the stored memories
+// and scope from the memory sources.
+func (d *deps) handleComplete(ctx context.Context, request *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	result := &mcp.CompleteResult{Completion: mcp.CompletionResultDetails{Values: []string{}}}
+	if request.Params.Ref == nil || request.Params.Ref.Type != "ref/prompt" {
+		return result, nil
+	}
+
+	value := request.Params.Argument.Value
+	switch request.Params.Argument.Name {
+	case "tags":
+		tags, err := d.store.GetTags()
+		if err != nil {
+			return nil, err
+		}
+		result.Completion = completeTags(value, tags)
+	case "scope":
+		result.Completion = completeValues(value, promptScopes)
+	}
+	return result, nil
+}
+
+// completeTags completes the last tag of a comma-separated list, leaving out
+// tags already in it. Each value is the whole list with the completed tag.
+func completeTags(value string, tags []string) mcp.CompletionResultDetails {
+	prefix, partial := "", value
+	chosen := make(map[string]bool)
+	if i := strings.LastIndex(value, ","); i >= 0 {
+		prefix, partial = value[:i+1]+" ", value[i+1:]
+		for _, t := range splitTags(value[:i]) {
+			chosen[strings.ToLower(t)] = true
+		}
+	}
+	var candidates []string
+	for _, t := range tags {
+		if !chosen[strings.ToLower(t)] {
+			candidates = append(candidates, t)
+		}
+	}
+
+	details := completeValues(partial, candidates)
+	for i, v := range details.Values {
+		details.Values[i] = prefix + v
+	}
+	return details
+}
+
+// completeValues returns the candidates that start with value, ignoring case
+// and surrounding space, in their given order.
+func completeValues(value string, candidates []string) mcp.CompletionResultDetails {
+	value = strings.ToLower(strings.TrimSpace(value))
+	details := mcp.CompletionResultDetails{Values: []string{}}
+	for _, c := range candidates {
+		if strings.HasPrefix(strings.ToLower(c), value) {
+			details.Total++
+			if len(details.Values) < maxCompletions {
+				details.Values = append(details.Values, c)
+			}
+		}
+	}
+	details.HasMore = details.Total > len(details.Values)
+	return details
+}
@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestCompleteTags(t *testing.T) {
+	tags := []string{"go", "editor", "Golang", "python"}
+
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"", []string{"go", "editor", "Golang", "python"}},
+		{"GO", []string{"go", "Golang"}},
+		{"go, ed", []string{"go, editor"}},
+		{"go,", []string{"go, editor", "go, Golang", "go, python"}},
+		{"editor, python,g", []string{"editor, python, go", "editor, python, Golang"}},
+		{"rust", []string{}},
+	}
+	for _, tt := range tests {
+		got := completeTags(tt.value, tags)
+		if !slices.Equal(got.Values, tt.want) {
+			t.Errorf("completeTags(%q) = %q, want %q", tt.value, got.Values, tt.want)
+		}
+		if got.Total != len(tt.want) || got.HasMore {
+			t.Errorf("completeTags(%q): unexpected total %d, has more %v", tt.value, got.Total, got.HasMore)
+		}
+	}
+}
+
+func TestHandleComplete_Scope(t *testing.T) {
+	d := newTestDeps(t)
+	request := &mcp.CompleteRequest{Params: &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "recall_context"},
+		Argument: mcp.CompleteParamsArgument{Name: "scope", Value: "ex"},
+	}}
+	result, err := d.handleComplete(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"explicit", "extracted"}; !slices.Equal(result.Completion.Values, want) {
+		t.Fatalf("got %q, want %q", result.Completion.Values, want)
+	}
+}
+
+func TestHandleRememberSession(t *testing.T) {
+	request := &mcp.GetPromptRequest{Params: &mcp.GetPromptParams{
+		Name:      "remember_session",
+		Arguments: map[string]string{"topic": "the release", "tags": "work, release"},
+	}}
+	result, err := handleRememberSession(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Messages[0].Content.(*mcp.TextContent).Text
+	for _, want := range []string{"the release", "memory_save", "work, release"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("prompt should mention %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestHandleRecallContext_EmptyTopic(t *testing.T) {
+	d := newTestDeps(t)
+	request := &mcp.GetPromptRequest{Params: &mcp.GetPromptParams{Name: "recall_context"}}
+	if _, err := d.handleRecallContext(context.Background(), request); err == nil {
+		t.Fatal("expected error for missing topic, got nil")
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/austiecodes/gomor/internal/memory/memtypes"
 	"github.com/austiecodes/gomor/internal/memory/memutils"
+	"github.com/austiecodes/gomor/internal/memory/retrieval"
 	"github.com/austiecodes/gomor/internal/memory/store"
 	"github.com/austiecodes/gomor/internal/provider"
 	"github.com/austiecodes/gomor/internal/utils"
@@ -36,17 +37,99 @@ func createMemoryList(memories []memtypes.MemoryItem, width, height int) list.Mo
 	l.Title = "Memories"
 	l.SetShowStatusBar(true)
 	l.SetFilteringEnabled(true)
+	l.Filter = memoryFilter(memories)
 	l.SetShowHelp(true)
 	l.AdditionalShortHelpKeys = func() []key.Binding {
 		return []key.Binding{
 			key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "add")),
 			key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
 			key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit")),
+			key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "pin")),
+			key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "review")),
 		}
 	}
 	return l
 }
 
+// createReviewList lists extracted memories awaiting review.
+func createReviewList(pending []memtypes.MemoryItem, width, height int) list.Model {
+	items := make([]list.Item, len(pending))
+	for i, mem := range pending {
+		items[i] = ReviewListItem{Memory: mem}
+	}
+
+	delegate := list.NewDefaultDelegate()
+	w := max(min(width-4, 80), 40)
+	h := max(min(height-6, 20), 10)
+
+	l := list.New(items, delegate, w, h)
+	l.Title = "Review extracted memories"
+	l.SetShowStatusBar(true)
+	l.SetFilteringEnabled(false)
+	l.SetShowHelp(true)
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "approve")),
+			key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "reject")),
+			key.NewBinding(key.WithKeys("A"), key.WithHelp("A", "approve all")),
+		}
+	}
+	return l
+}
+
+// splitByStatus separates retrievable memories from those awaiting review.
+// Memories archived by consolidation are left out.
+func splitByStatus(memories []memtypes.MemoryItem) (active, pending []memtypes.MemoryItem) {
+	for _, mem := range memories {
+		switch mem.Status {
+		case memtypes.StatusPending:
+			pending = append(pending, mem)
+		case memtypes.StatusArchived:
+		default:
+			active = append(active, mem)
+		}
+	}
+	return active, pending
+}
+
+// memoryFilter returns a list filter that understands retrieval query
+// qualifiers (tag:go source:explicit after:2025-01-01 ...) and fuzzy-matches
+// the remaining text. memories must be in the same order as the list items.
+func memoryFilter(memories []memtypes.MemoryItem) list.FilterFunc {
+	return func(term string, targets []string) []list.Rank {
+		parsed, err := retrieval.ParseQueryStrict(term)
+		if err != nil {
+			return nil // the error is shown below the list
+		}
+
+		var indexes []int
+		var subset []string
+		for i, mem := range memories {
+			if i < len(targets) && parsed.Options.Filter.Match(mem) {
+				indexes = append(indexes, i)
+				subset = append(subset, targets[i])
+			}
+		}
+
+		var ranks []list.Rank
+		if parsed.Text == "" {
+			for _, i := range indexes {
+				ranks = append(ranks, list.Rank{Index: i})
+			}
+		} else {
+			ranks = list.DefaultFilter(parsed.Text, subset)
+			for i := range ranks {
+				ranks[i].Index = indexes[ranks[i].Index]
+			}
+		}
+
+		if parsed.Options.TopK > 0 && len(ranks) > parsed.Options.TopK {
+			ranks = ranks[:parsed.Options.TopK]
+		}
+		return ranks
+	}
+}
+
 func createAddEditInputs(mem *memtypes.MemoryItem) []textinput.Model {
 	inputs := make([]textinput.Model, 2)
 
@@ -169,24 +252,60 @@ func updateMemory(id, text string, tags []string) tea.Cmd {
 		}
 		defer memStore.Close()
 
-		// Delete old and save new (simple update strategy)
-		_ = memStore.DeleteMemory(id)
-
+		// Update in place, keeping the ID, creation time and pin
 		item := &memtypes.MemoryItem{
+			ID:        id,
 			Text:      text,
 			Tags:      tags,
-			Source:    memtypes.SourceExplicit,
 			Provider:  embeddingModel.Provider,
 			ModelID:   embeddingModel.ModelID,
 			Dim:       len(normalizedEmbedding),
 			Embedding: normalizedEmbedding,
 		}
 
-		err = memStore.SaveMemory(item)
+		err = memStore.UpdateMemory(item)
 		return MemorySavedMsg{Err: err}
 	}
 }
 
+// reviewMemories approves pending memories, making them retrievable, or
+// rejects them by deleting them.
+func reviewMemories(ids []string, approve bool) tea.Cmd {
+	return func() tea.Msg {
+		memStore, err := store.NewStore()
+		if err != nil {
+			return MemoryReviewedMsg{Err: err}
+		}
+		defer memStore.Close()
+
+		for _, id := range ids {
+			if approve {
+				err = memStore.SetMemoryStatus(id, memtypes.StatusActive)
+			} else {
+				err = memStore.DeleteMemory(id)
+			}
+			if err != nil {
+				return MemoryReviewedMsg{Err: err}
+			}
+		}
+		return MemoryReviewedMsg{}
+	}
+}
+
+// pinMemory pins or unpins a memory. Pinned memories always go into the profile document.
+func pinMemory(id string, pinned bool) tea.Cmd {
+	return func() tea.Msg {
+		memStore, err := store.NewStore()
+		if err != nil {
+			return MemoryPinnedMsg{Err: err}
+		}
+		defer memStore.Close()
+
+		err = memStore.SetMemoryPinned(id, pinned)
+		return MemoryPinnedMsg{Pinned: pinned, Err: err}
+	}
+}
+
 func deleteMemory(id string) tea.Cmd {
 	return func() tea.Msg {
 		memStore, err := store.NewStore()
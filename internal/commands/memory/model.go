@@ -1,6 +1,8 @@
 package memory
 
 import (
+	"fmt"
+
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -15,9 +17,10 @@ func initialModel() Model {
 	l.SetShowHelp(true)
 
 	return Model{
-		Screen:    ScreenMemoryList,
-		List:      l,
-		StatusMsg: "Loading memories...",
+		Screen:     ScreenMemoryList,
+		List:       l,
+		ReviewList: createReviewList(nil, 0, 0),
+		StatusMsg:  "Loading memories...",
 	}
 }
 
@@ -31,9 +34,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.Width = msg.Width
 		m.Height = msg.Height
 		m.List.SetSize(min(msg.Width-4, 80), min(msg.Height-6, 20))
+		m.ReviewList.SetSize(min(msg.Width-4, 80), min(msg.Height-6, 20))
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.Screen == ScreenMemoryList && m.List.FilterState() == list.Filtering && msg.String() != "ctrl+c" {
+			break // let the filter box handle typing, including 'q' and esc
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			if m.Screen == ScreenMemoryList {
@@ -63,10 +70,43 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.Err = msg.Err
 			return m, nil
 		}
-		m.Memories = msg.Memories
+		m.Memories, m.Pending = splitByStatus(msg.Memories)
 		m.List = createMemoryList(m.Memories, m.Width, m.Height)
+		if len(m.Pending) > 0 {
+			m.List.Title = fmt.Sprintf("Memories (%d to review)", len(m.Pending))
+		}
+		selected := m.ReviewList.Index()
+		m.ReviewList = createReviewList(m.Pending, m.Width, m.Height)
+		m.ReviewList.Select(min(selected, max(len(m.Pending)-1, 0)))
+		if m.Screen == ScreenReview && len(m.Pending) == 0 {
+			m.Screen = ScreenMemoryList
+			m.StatusMsg = "Review queue is empty"
+		}
 		return m, nil
 
+	case MemoryReviewedMsg:
+		m.StatusMsg = ""
+		if msg.Err != nil {
+			m.Err = msg.Err
+			return m, nil
+		}
+		m.Err = nil
+		return m, loadMemories()
+
+	case MemoryPinnedMsg:
+		m.StatusMsg = ""
+		if msg.Err != nil {
+			m.Err = msg.Err
+			return m, nil
+		}
+		m.Err = nil
+		if msg.Pinned {
+			m.StatusMsg = "Memory pinned to the profile"
+		} else {
+			m.StatusMsg = "Memory unpinned"
+		}
+		return m, loadMemories()
+
 	case MemorySavedMsg:
 		m.StatusMsg = ""
 		if msg.Err != nil {
@@ -105,6 +145,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateMemoryEdit(msg)
 	case ScreenConfirmDelete:
 		return m.updateConfirmDelete(msg)
+	case ScreenReview:
+		return m.updateReview(msg)
 	}
 
 	return m, nil
@@ -4,12 +4,21 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/austiecodes/gomor/internal/memory/retrieval"
 )
 
 func (m *Model) updateMemoryList(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// While the filter box is focused, keys are filter text, not shortcuts
+	filtering := m.List.FilterState() == list.Filtering
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if filtering {
+			break
+		}
 		switch msg.String() {
 		case "enter":
 			if len(m.Memories) == 0 {
@@ -48,11 +57,69 @@ func (m *Model) updateMemoryList(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.FocusedInput = 0
 			m.Screen = ScreenMemoryEdit
 			return *m, m.TextInputs[0].Focus()
+
+		case "p":
+			// Pin or unpin selected memory
+			if len(m.Memories) == 0 {
+				return *m, nil
+			}
+			selected := m.List.SelectedItem().(MemoryListItem)
+			return *m, pinMemory(selected.Memory.ID, !selected.Memory.Pinned)
+
+		case "r":
+			// Review extracted memories
+			if len(m.Pending) == 0 {
+				m.StatusMsg = "No extracted memories to review"
+				return *m, nil
+			}
+			m.StatusMsg = ""
+			m.Screen = ScreenReview
+			return *m, nil
 		}
 	}
 
 	var cmd tea.Cmd
 	m.List, cmd = m.List.Update(msg)
+
+	// Report invalid filter qualifiers instead of silently showing no matches
+	m.FilterErr = nil
+	if m.List.FilterState() != list.Unfiltered {
+		if _, err := retrieval.ParseQueryStrict(m.List.FilterValue()); err != nil {
+			m.FilterErr = err
+		}
+	}
+	return *m, cmd
+}
+
+func (m *Model) updateReview(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "a", "x":
+			if len(m.Pending) == 0 {
+				return *m, nil
+			}
+			selected := m.ReviewList.SelectedItem().(ReviewListItem)
+			approve := msg.String() == "a"
+			if approve {
+				m.StatusMsg = "Approving..."
+			} else {
+				m.StatusMsg = "Rejecting..."
+			}
+			return *m, reviewMemories([]string{selected.Memory.ID}, approve)
+
+		case "A":
+			ids := make([]string, len(m.Pending))
+			for i, mem := range m.Pending {
+				ids[i] = mem.ID
+			}
+			m.StatusMsg = "Approving..."
+			return *m, reviewMemories(ids, true)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.ReviewList, cmd = m.ReviewList.Update(msg)
 	return *m, cmd
 }
 
@@ -71,6 +138,11 @@ func (m *Model) updateMemoryDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Delete this memory
 			m.Screen = ScreenConfirmDelete
 			return *m, nil
+
+		case "p":
+			// Pin or unpin this memory
+			m.SelectedMemory.Pinned = !m.SelectedMemory.Pinned
+			return *m, pinMemory(m.SelectedMemory.ID, m.SelectedMemory.Pinned)
 		}
 	}
 
@@ -175,9 +247,20 @@ func (m *Model) renderView() string {
 			s.WriteString("\n\n")
 			s.WriteString(SubtitleStyle.Render("No memories stored yet."))
 			s.WriteString("\n\n")
-			s.WriteString(HelpStyle.Render("Press 'a' to add a new memory, 'q' to quit"))
+			if len(m.Pending) > 0 {
+				s.WriteString(HelpStyle.Render(fmt.Sprintf("Press 'r' to review %d extracted memories, 'a' to add a new memory, 'q' to quit", len(m.Pending))))
+			} else {
+				s.WriteString(HelpStyle.Render("Press 'a' to add a new memory, 'q' to quit"))
+			}
 		} else {
 			s.WriteString(m.List.View())
+			if m.FilterErr != nil {
+				s.WriteString("\n")
+				s.WriteString(ErrorStyle.Render(fmt.Sprintf("Filter: %v", m.FilterErr)))
+			} else if m.List.FilterState() == list.Filtering {
+				s.WriteString("\n")
+				s.WriteString(HelpStyle.Render("Qualifiers: tag:<name> source:explicit|extracted|document after:<date> before:<date> limit:<n>"))
+			}
 		}
 
 	case ScreenMemoryDetail:
@@ -205,6 +288,27 @@ func (m *Model) renderView() string {
 			s.WriteString(DetailValueStyle.Render(string(m.SelectedMemory.Source)))
 			s.WriteString("\n\n")
 
+			if m.SelectedMemory.Confidence > 0 {
+				s.WriteString(DetailLabelStyle.Render("Confidence:"))
+				s.WriteString(" ")
+				s.WriteString(DetailValueStyle.Render(fmt.Sprintf("%.2f", m.SelectedMemory.Confidence)))
+				s.WriteString("\n\n")
+			}
+
+			if m.SelectedMemory.Pinned {
+				s.WriteString(DetailLabelStyle.Render("Pinned:"))
+				s.WriteString(" ")
+				s.WriteString(DetailValueStyle.Render("yes, always in the profile"))
+				s.WriteString("\n\n")
+			}
+
+			if m.SelectedMemory.Importance > 0 {
+				s.WriteString(DetailLabelStyle.Render("Importance:"))
+				s.WriteString(" ")
+				s.WriteString(DetailValueStyle.Render(fmt.Sprintf("%.2f", m.SelectedMemory.Importance)))
+				s.WriteString("\n\n")
+			}
+
 			if len(m.SelectedMemory.Tags) > 0 {
 				s.WriteString(DetailLabelStyle.Render("Tags:"))
 				s.WriteString(" ")
@@ -217,7 +321,7 @@ func (m *Model) renderView() string {
 				s.WriteString("\n\n")
 			}
 
-			s.WriteString(HelpStyle.Render("Press 'e' to edit, 'd' to delete, Esc to go back"))
+			s.WriteString(HelpStyle.Render("Press 'e' to edit, 'd' to delete, 'p' to pin or unpin, Esc to go back"))
 		}
 
 	case ScreenMemoryAdd:
@@ -246,6 +350,11 @@ func (m *Model) renderView() string {
 		s.WriteString("\n\n")
 		s.WriteString(HelpStyle.Render("Press Enter to save, Esc to cancel, Tab to navigate"))
 
+	case ScreenReview:
+		s.WriteString(m.ReviewList.View())
+		s.WriteString("\n")
+		s.WriteString(HelpStyle.Render("Approved memories become retrievable; rejected ones are deleted. Esc to go back"))
+
 	case ScreenConfirmDelete:
 		s.WriteString(WarningStyle.Render("Confirm Delete"))
 		s.WriteString("\n\n")
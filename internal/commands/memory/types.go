@@ -1,6 +1,9 @@
 package memory
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -17,6 +20,7 @@ const (
 	ScreenMemoryAdd
 	ScreenMemoryEdit
 	ScreenConfirmDelete
+	ScreenReview
 )
 
 // MemoryListItem implements list.Item interface for memory display
@@ -24,20 +28,44 @@ type MemoryListItem struct {
 	Memory memtypes.MemoryItem
 }
 
-func (i MemoryListItem) Title() string       { return i.Memory.Text }
-func (i MemoryListItem) Description() string { return i.Memory.CreatedAt.Format("2006-01-02 15:04") }
+func (i MemoryListItem) Title() string { return i.Memory.Text }
+func (i MemoryListItem) Description() string {
+	desc := i.Memory.CreatedAt.Format("2006-01-02 15:04")
+	if i.Memory.Pinned {
+		desc += " · pinned"
+	}
+	return desc
+}
 func (i MemoryListItem) FilterValue() string { return i.Memory.Text }
 
+// ReviewListItem implements list.Item interface for extracted memories awaiting review
+type ReviewListItem struct {
+	Memory memtypes.MemoryItem
+}
+
+func (i ReviewListItem) Title() string { return i.Memory.Text }
+func (i ReviewListItem) Description() string {
+	desc := fmt.Sprintf("confidence %.2f", i.Memory.Confidence)
+	if len(i.Memory.Tags) > 0 {
+		desc += " · " + strings.Join(i.Memory.Tags, ", ")
+	}
+	return desc
+}
+func (i ReviewListItem) FilterValue() string { return i.Memory.Text }
+
 // Model is the Bubble Tea model for the memory command
 type Model struct {
 	Screen         Screen
 	List           list.Model
+	ReviewList     list.Model
 	Viewport       viewport.Model
 	TextInputs     []textinput.Model
 	FocusedInput   int
 	SelectedMemory *memtypes.MemoryItem
 	Memories       []memtypes.MemoryItem
+	Pending        []memtypes.MemoryItem
 	Err            error
+	FilterErr      error
 	StatusMsg      string
 	Quitting       bool
 	Width          int
@@ -59,3 +87,14 @@ type MemorySavedMsg struct {
 type MemoryDeletedMsg struct {
 	Err error
 }
+
+// MemoryPinnedMsg is sent when a memory is pinned or unpinned
+type MemoryPinnedMsg struct {
+	Pinned bool
+	Err    error
+}
+
+// MemoryReviewedMsg is sent when pending memories are approved or rejected
+type MemoryReviewedMsg struct {
+	Err error
+}
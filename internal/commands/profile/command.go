@@ -0,0 +1,78 @@
+package profile
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// ProfileCmd is the command to manage named profiles
+var ProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage gomor profiles",
+	Long: `Manage named profiles. Each profile has its own settings and memory database.
+The profile is selected by the --profile flag, the GOMOR_PROFILE environment variable,
+or the active profile set with 'gomor profile use'. GOMOR_HOME changes the base directory.`,
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available profiles",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		names, err := utils.ListProfiles()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to list profiles: %v\n", err)
+			os.Exit(1)
+		}
+
+		current, err := utils.CurrentProfile()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to resolve current profile: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, name := range names {
+			marker := " "
+			if name == current {
+				marker = "*"
+			}
+			fmt.Printf("%s %s\n", marker, name)
+		}
+	},
+}
+
+var createCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a new profile",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := utils.CreateProfile(args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create profile: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Profile %q created. Run 'gomor --profile %s set' to configure it.\n", args[0], args[0])
+	},
+}
+
+var useCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Set the active profile",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := utils.UseProfile(args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to switch profile: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Now using profile %q.\n", args[0])
+	},
+}
+
+func init() {
+	ProfileCmd.AddCommand(listCmd)
+	ProfileCmd.AddCommand(createCmd)
+	ProfileCmd.AddCommand(useCmd)
+}
@@ -5,12 +5,27 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/austiecodes/gomor/internal/utils"
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "gomor",
 	Short: "gomor is a MCP server for memory management",
 	Long:  `gomor is a MCP (Model Context Protocol) server that provides memory management capabilities.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if profile, _ := cmd.Flags().GetString("profile"); profile != "" {
+			if err := utils.ValidateProfileName(profile); err != nil {
+				return err
+			}
+			utils.SetProfile(profile)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().String("profile", "", "profile to use (overrides $GOMOR_PROFILE and the active profile)")
 }
 
 // AddCommand adds a subcommand to the root command
@@ -0,0 +1,104 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/memory/retrieval"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/provider"
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// SearchCmd is the command to search memories from the command line
+var SearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search memories",
+	Long: `Search memories with the same hybrid retrieval used by the memory_retrieve MCP tool.
+
+The query may contain qualifiers to narrow the results:
+  tag:<name>         memories with this tag (repeat or use tag:a,b for several)
+  source:<source>    explicit, extracted or document
+  after:<date>       created on or after YYYY-MM-DD (or RFC 3339)
+  before:<date>      created before YYYY-MM-DD (or RFC 3339)
+  limit:<n>          return at most n memories
+  minscore:<score>   drop results scoring below 0-1
+
+Use --explain to also print how the results were found: the transformed queries,
+the hits of each vector and full-text search, the terms behind each fused score
+and how long each stage took.
+
+Example: gomor search tag:go source:explicit after:2025-01-01 error handling`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		explain, _ := cmd.Flags().GetBool("explain")
+		if err := runSearch(strings.Join(args, " "), explain); err != nil {
+			fmt.Fprintf(os.Stderr, "Search error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	SearchCmd.Flags().Bool("explain", false, "show how the results were found and scored")
+}
+
+func runSearch(query string, explain bool) error {
+	parsed, err := retrieval.ParseQueryStrict(query)
+	if err != nil {
+		return err
+	}
+	if parsed.Text == "" {
+		return fmt.Errorf("query must contain search text besides qualifiers")
+	}
+	parsed.Options.Explain = explain
+
+	config, err := utils.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if config.Model.EmbeddingModel == nil {
+		return fmt.Errorf("embedding model not configured. Run 'gomor set' to configure")
+	}
+
+	memStore, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open memory store: %w", err)
+	}
+	defer memStore.Close()
+
+	embeddingModel := *config.Model.EmbeddingModel
+	embClient, err := provider.NewEmbeddingClient(config, embeddingModel.Provider)
+	if err != nil {
+		return fmt.Errorf("failed to create embedding client: %w", err)
+	}
+
+	// Query client for LLM transformations (optional, may be nil)
+	var queryClient client.QueryClient
+	toolModel := types.Model{}
+	if config.Model.ToolModel != nil {
+		toolModel = *config.Model.ToolModel
+		queryClient, _ = provider.NewQueryClient(config, toolModel.Provider)
+	}
+
+	ret := retrieval.NewRetriever(memStore, embClient, queryClient, embeddingModel, toolModel, config.Memory)
+	ret.SetEmbeddingClientFactory(func(p string) (client.EmbeddingClient, error) {
+		return provider.NewEmbeddingClient(config, p)
+	})
+	response, err := ret.RetrieveWithOptions(context.Background(), parsed.Text, parsed.Options)
+	if err != nil {
+		return fmt.Errorf("retrieval failed: %w", err)
+	}
+
+	fmt.Print(retrieval.FormatAsTextWithLimit(response, config.Memory.MaxInjectedChars))
+	if response.Explain != nil {
+		fmt.Print("\n" + retrieval.FormatTrace(response.Explain))
+	}
+	return nil
+}
@@ -119,7 +119,7 @@ func createModelList(models []string, mt ModelType) list.Model {
 }
 
 func createMemoryConfigInputs(config *utils.Config) []textinput.Model {
-	inputs := make([]textinput.Model, 3)
+	inputs := make([]textinput.Model, 9)
 
 	// Min Similarity input
 	inputs[0] = textinput.New()
@@ -142,6 +142,48 @@ func createMemoryConfigInputs(config *utils.Config) []textinput.Model {
 	inputs[2].Width = 20
 	inputs[2].SetValue(formatInt(config.Memory.HistoryTopK))
 
+	// Fusion strategy input
+	inputs[3] = textinput.New()
+	inputs[3].Placeholder = utils.FusionStrategyLegacy
+	inputs[3].CharLimit = 10
+	inputs[3].Width = 20
+	inputs[3].SetValue(config.Memory.FusionStrategy)
+
+	// Vector weight input
+	inputs[4] = textinput.New()
+	inputs[4].Placeholder = "0.60"
+	inputs[4].CharLimit = 10
+	inputs[4].Width = 20
+	inputs[4].SetValue(formatFloat(config.Memory.VectorWeight))
+
+	// FTS weight input
+	inputs[5] = textinput.New()
+	inputs[5].Placeholder = "0.40"
+	inputs[5].CharLimit = 10
+	inputs[5].Width = 20
+	inputs[5].SetValue(formatFloat(config.Memory.FTSWeight))
+
+	// RRF K input
+	inputs[6] = textinput.New()
+	inputs[6].Placeholder = "60"
+	inputs[6].CharLimit = 5
+	inputs[6].Width = 20
+	inputs[6].SetValue(formatInt(config.Memory.RRFK))
+
+	// Recency weight input
+	inputs[7] = textinput.New()
+	inputs[7].Placeholder = "0.00"
+	inputs[7].CharLimit = 10
+	inputs[7].Width = 20
+	inputs[7].SetValue(formatFloat(config.Memory.RecencyWeight))
+
+	// Recency half-life input
+	inputs[8] = textinput.New()
+	inputs[8].Placeholder = "90"
+	inputs[8].CharLimit = 10
+	inputs[8].Width = 20
+	inputs[8].SetValue(formatFloat(config.Memory.RecencyHalfLifeDays))
+
 	return inputs
 }
 
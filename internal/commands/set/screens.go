@@ -7,6 +7,7 @@ import (
 
 	"github.com/austiecodes/gomor/internal/consts"
 	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -223,9 +224,57 @@ func (m *Model) updateMemoryConfig(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return *m, nil
 			}
 
+			fusion := strings.TrimSpace(m.TextInputs[3].Value())
+			switch fusion {
+			case utils.FusionStrategyRRF, utils.FusionStrategyWeighted, utils.FusionStrategyLegacy:
+			default:
+				m.Err = fmt.Errorf("fusion_strategy must be one of: rrf, weighted, legacy")
+				return *m, nil
+			}
+
+			vectorWeight, err := strconv.ParseFloat(m.TextInputs[4].Value(), 64)
+			if err != nil || vectorWeight < 0 {
+				m.Err = fmt.Errorf("vector_weight must be a non-negative number")
+				return *m, nil
+			}
+
+			ftsWeight, err := strconv.ParseFloat(m.TextInputs[5].Value(), 64)
+			if err != nil || ftsWeight < 0 {
+				m.Err = fmt.Errorf("fts_weight must be a non-negative number")
+				return *m, nil
+			}
+			if vectorWeight+ftsWeight == 0 {
+				m.Err = fmt.Errorf("vector_weight and fts_weight cannot both be 0")
+				return *m, nil
+			}
+
+			rrfK, err := strconv.Atoi(m.TextInputs[6].Value())
+			if err != nil || rrfK < 1 {
+				m.Err = fmt.Errorf("rrf_k must be a positive integer")
+				return *m, nil
+			}
+
+			recencyWeight, err := strconv.ParseFloat(m.TextInputs[7].Value(), 64)
+			if err != nil || recencyWeight < 0 || recencyWeight > 1 {
+				m.Err = fmt.Errorf("recency_weight must be a number between 0 and 1")
+				return *m, nil
+			}
+
+			halfLife, err := strconv.ParseFloat(m.TextInputs[8].Value(), 64)
+			if err != nil || halfLife <= 0 {
+				m.Err = fmt.Errorf("recency_half_life_days must be a positive number")
+				return *m, nil
+			}
+
 			m.Config.Memory.MinSimilarity = minSim
 			m.Config.Memory.MemoryTopK = memTopK
 			m.Config.Memory.HistoryTopK = histTopK
+			m.Config.Memory.FusionStrategy = fusion
+			m.Config.Memory.VectorWeight = vectorWeight
+			m.Config.Memory.FTSWeight = ftsWeight
+			m.Config.Memory.RRFK = rrfK
+			m.Config.Memory.RecencyWeight = recencyWeight
+			m.Config.Memory.RecencyHalfLifeDays = halfLife
 
 			return *m, saveConfig(m.Config)
 		}
@@ -354,6 +403,12 @@ func (m *Model) renderView() string {
 			"Min Similarity (0.0-1.0, default: 0.80)",
 			"Memory Top K (default: 10)",
 			"History Top K (default: 10)",
+			"Fusion Strategy (rrf, weighted or legacy, default: rrf)",
+			"Vector Weight (default: 0.60, used by rrf and weighted)",
+			"FTS Weight (default: 0.40, used by rrf and weighted)",
+			"RRF K (default: 60)",
+			"Recency Weight (0.0-1.0, 0 disables decay, default: 0.00)",
+			"Recency Half-Life in Days (default: 90)",
 		}
 		for i, input := range m.TextInputs {
 			s.WriteString(InputLabelStyle.Render(labels[i]))
@@ -0,0 +1,84 @@
+package whoami
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/memory/profile"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/provider"
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// WhoamiCmd is the command to show the profile document
+var WhoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the profile gomor keeps about you",
+	Long: `Print the profile document: a short Markdown summary of who you are, written by
+the tool model from your pinned memories and those with an importance of at least
+profile_min_importance (0.7). MCP clients read the same document as the
+gomor://profile resource.
+
+The profile is rewritten when the memories it is written from change, revising
+the previous version rather than starting over. Use --cached to print the stored
+version without updating it.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cached, _ := cmd.Flags().GetBool("cached")
+		if err := runWhoami(cached); err != nil {
+			fmt.Fprintf(os.Stderr, "Whoami error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	WhoamiCmd.Flags().Bool("cached", false, "print the stored profile without updating it")
+}
+
+func runWhoami(cached bool) error {
+	config, err := utils.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	memStore, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to open memory store: %w", err)
+	}
+	defer memStore.Close()
+
+	var current *store.Profile
+	if cached {
+		if current, err = memStore.GetProfile(); err != nil {
+			return err
+		}
+		if current == nil {
+			return fmt.Errorf("no profile yet. Run 'gomor whoami' without --cached to write one")
+		}
+	} else {
+		var queryClient client.QueryClient
+		var toolModel types.Model
+		if config.Model.ToolModel != nil {
+			toolModel = *config.Model.ToolModel
+			if queryClient, err = provider.NewQueryClient(config, toolModel.Provider); err != nil {
+				return fmt.Errorf("failed to create query client: %w", err)
+			}
+		}
+		generator := profile.NewGenerator(memStore, queryClient, toolModel, config.Memory)
+		if current, _, err = generator.Refresh(context.Background()); err != nil {
+			return err
+		}
+	}
+
+	// Details go to stderr so the profile itself can be piped
+	fmt.Fprintf(os.Stderr, "Profile version %d, written %s from %d memories\n\n",
+		current.Version, current.CreatedAt.Format("2006-01-02 15:04"), len(current.MemoryIDs))
+	fmt.Print(current.Content)
+	return nil
+}
@@ -0,0 +1,356 @@
+// Package consolidate merges groups of overlapping memories into single
+// memories. The originals are linked to the merged memory and archived.
+package consolidate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/memory/memutils"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/types"
+)
+
+// Clustering defaults.
+const (
+	DefaultSimilarity     = 0.85
+	DefaultMaxClusterSize = 8
+)
+
+// mergeSchema is the structured response requested from tool_model.
+var mergeSchema = client.JSONSchema{
+	Name:        "merged_memory",
+	Description: "One memory that replaces several overlapping ones",
+	Schema: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"text": map[string]any{"type": "string"},
+			"tags": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		},
+		"required":             []string{"text", "tags"},
+		"additionalProperties": false,
+	},
+}
+
+// Options configures a consolidation.
+type Options struct {
+	EmbeddingClient client.EmbeddingClient
+	EmbeddingModel  types.Model
+	QueryClient     client.QueryClient
+	ToolModel       types.Model
+	// Similarity is the cosine similarity every pair in a cluster must reach
+	// (default DefaultSimilarity).
+	Similarity float64
+	// MaxClusterSize caps how many memories are merged at once (default DefaultMaxClusterSize).
+	MaxClusterSize int
+}
+
+// Merge is one planned consolidation: Sources are replaced by a memory with Text and Tags.
+type Merge struct {
+	Sources       []store.MemoryItem
+	MinSimilarity float64 // lowest similarity between two sources
+	Text          string
+	Tags          []string
+}
+
+// Plan lists the merges a consolidation would make.
+type Plan struct {
+	Considered int // active memories embedded with the configured model
+	Merges     []Merge
+}
+
+// Archived returns how many memories applying the plan archives.
+func (p *Plan) Archived() int {
+	n := 0
+	for _, m := range p.Merges {
+		n += len(m.Sources)
+	}
+	return n
+}
+
+// NewPlan clusters the active memories by embedding similarity and asks the
+// tool model to write a merged memory for each cluster. It changes nothing.
+func NewPlan(ctx context.Context, s *store.Store, opts Options) (*Plan, error) {
+	if opts.QueryClient == nil {
+		return nil, fmt.Errorf("tool model not configured. Run 'gomor set' to configure")
+	}
+
+	memories, err := s.GetMemories(store.MemoryFilter{})
+	if err != nil {
+		return nil, err
+	}
+	var candidates []store.MemoryItem
+	for _, m := range memories {
+		// Document chunks belong to their document and are replaced by re-ingesting it
+		if m.DocumentID != "" || m.Provider != opts.EmbeddingModel.Provider || m.ModelID != opts.EmbeddingModel.ModelID {
+			continue
+		}
+		candidates = append(candidates, m)
+	}
+
+	plan := &Plan{Considered: len(candidates)}
+	for _, cluster := range Cluster(candidates, opts.similarity(), opts.maxClusterSize()) {
+		merge, err := mergeCluster(ctx, opts, cluster)
+		if err != nil {
+			return nil, err
+		}
+		plan.Merges = append(plan.Merges, merge)
+	}
+	return plan, nil
+}
+
+func (o Options) similarity() float64 {
+	if o.Similarity <= 0 {
+		return DefaultSimilarity
+	}
+	return o.Similarity
+}
+
+func (o Options) maxClusterSize() int {
+	if o.MaxClusterSize < 2 {
+		return DefaultMaxClusterSize
+	}
+	return o.MaxClusterSize
+}
+
+// Cluster groups memories in which every pair is at least threshold similar,
+// with at most maxSize memories per group. Memories are taken oldest first:
+// each unclustered memory seeds a group, and the others join in order of
+// similarity to the seed as long as they are similar enough to every member.
+// Groups of one are dropped. Memories must have comparable, normalized embeddings.
+func Cluster(memories []store.MemoryItem, threshold float64, maxSize int) [][]store.MemoryItem {
+	ordered := append([]store.MemoryItem(nil), memories...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].CreatedAt.Before(ordered[j].CreatedAt) })
+
+	n := len(ordered)
+	sim := func(i, j int) float64 {
+		if len(ordered[i].Embedding) != len(ordered[j].Embedding) {
+			return 0
+		}
+		return memutils.DotProduct(ordered[i].Embedding, ordered[j].Embedding)
+	}
+
+	used := make([]bool, n)
+	var clusters [][]store.MemoryItem
+	for seed := 0; seed < n; seed++ {
+		if used[seed] {
+			continue
+		}
+
+		var near []int
+		for j := seed + 1; j < n; j++ {
+			if !used[j] && sim(seed, j) >= threshold {
+				near = append(near, j)
+			}
+		}
+		sort.SliceStable(near, func(a, b int) bool { return sim(seed, near[a]) > sim(seed, near[b]) })
+
+		members := []int{seed}
+		for _, j := range near {
+			if len(members) == maxSize {
+				break
+			}
+			fits := true
+			for _, m := range members {
+				if sim(m, j) < threshold {
+					fits = false
+					break
+				}
+			}
+			if fits {
+				members = append(members, j)
+			}
+		}
+		if len(members) < 2 {
+			continue
+		}
+
+		cluster := make([]store.MemoryItem, len(members))
+		for i, m := range members {
+			used[m] = true
+			cluster[i] = ordered[m]
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters
+}
+
+// mergeCluster asks the tool model for one memory that replaces cluster.
+func mergeCluster(ctx context.Context, opts Options, cluster []store.MemoryItem) (Merge, error) {
+	merge := Merge{Sources: cluster, MinSimilarity: 1}
+	for i := range cluster {
+		for j := i + 1; j < len(cluster); j++ {
+			merge.MinSimilarity = min(merge.MinSimilarity, memutils.DotProduct(cluster[i].Embedding, cluster[j].Embedding))
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`These memories about a user overlap. Merge them into one memory that keeps every distinct fact and preference, drops repetition, and is a short standalone statement about the user. Where they conflict, prefer the most recent. Keep the useful tags.
+
+Respond with JSON: {"text": "...", "tags": ["..."]}
+
+Memories (oldest first):
+`)
+	for _, m := range cluster {
+		sb.WriteString(fmt.Sprintf("- [%s] %s", m.CreatedAt.Format(time.DateOnly), m.Text))
+		if len(m.Tags) > 0 {
+			sb.WriteString(fmt.Sprintf(" (tags: %s)", strings.Join(m.Tags, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+
+	response, err := client.CompleteJSON(ctx, opts.QueryClient, opts.ToolModel, sb.String(), mergeSchema)
+	if err != nil {
+		return merge, fmt.Errorf("failed to merge memories: %w", err)
+	}
+	var parsed struct {
+		Text string   `json:"text"`
+		Tags []string `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(client.ExtractJSONObject(response)), &parsed); err != nil {
+		return merge, fmt.Errorf("failed to parse merged memory: %w", err)
+	}
+	merge.Text = strings.TrimSpace(parsed.Text)
+	if merge.Text == "" {
+		return merge, fmt.Errorf("tool model returned an empty merged memory")
+	}
+
+	seen := make(map[string]bool)
+	for _, t := range parsed.Tags {
+		if t = strings.TrimSpace(t); t != "" && !seen[strings.ToLower(t)] {
+			seen[strings.ToLower(t)] = true
+			merge.Tags = append(merge.Tags, t)
+		}
+	}
+	return merge, nil
+}
+
+// Apply embeds and saves the merged memories of plan, archiving their sources.
+// It holds the maintenance lock so it doesn't run alongside a reindex. A
+// merge whose sources changed since the plan was made, e.g. edited or merged
+// by another consolidation, is skipped, and the skipped clusters are
+// reported in the error once the others are applied.
+func Apply(ctx context.Context, s *store.Store, plan *Plan, opts Options) error {
+	if len(plan.Merges) == 0 {
+		return nil
+	}
+	if opts.EmbeddingClient == nil {
+		return fmt.Errorf("no embedding client configured")
+	}
+
+	lock, err := s.AcquireMaintenanceLock(ctx)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	texts := make([]string, len(plan.Merges))
+	for i, m := range plan.Merges {
+		texts[i] = m.Text
+	}
+	embeddings, err := opts.EmbeddingClient.EmbedBatch(ctx, opts.EmbeddingModel, texts)
+	if err != nil {
+		return fmt.Errorf("failed to embed merged memories: %w", err)
+	}
+	if len(embeddings) != len(texts) {
+		return fmt.Errorf("failed to embed merged memories: got %d embeddings for %d memories", len(embeddings), len(texts))
+	}
+
+	var stale []string
+	for i, m := range plan.Merges {
+		embedding := memutils.NormalizeVector(embeddings[i])
+		pinned, importance := profileWeight(m.Sources)
+		merged := &store.MemoryItem{
+			Text:       m.Text,
+			Tags:       m.Tags,
+			Source:     mergedSource(m.Sources),
+			CreatedAt:  newest(m.Sources),
+			Provider:   opts.EmbeddingModel.Provider,
+			ModelID:    opts.EmbeddingModel.ModelID,
+			Dim:        len(embedding),
+			Embedding:  embedding,
+			Pinned:     pinned,
+			Importance: importance,
+		}
+		err := s.ConsolidateMemories(merged, m.Sources)
+		if errors.Is(err, store.ErrMemoryChanged) {
+			stale = append(stale, strconv.Itoa(i+1))
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("skipped cluster %s: its memories changed since the plan was made; run 'gomor consolidate' again to re-plan",
+			strings.Join(stale, ", "))
+	}
+	return nil
+}
+
+// mergedSource keeps the sources' common source, or explicit when they differ.
+func mergedSource(sources []store.MemoryItem) store.MemorySource {
+	for _, s := range sources[1:] {
+		if s.Source != sources[0].Source {
+			return store.SourceExplicit
+		}
+	}
+	return sources[0].Source
+}
+
+// profileWeight returns whether any source is pinned and the highest
+// importance among them, so a merge keeps its facts in the profile.
+func profileWeight(sources []store.MemoryItem) (pinned bool, importance float64) {
+	for _, s := range sources {
+		pinned = pinned || s.Pinned
+		importance = max(importance, s.Importance)
+	}
+	return pinned, importance
+}
+
+// newest returns the creation time of the most recent source, so merging
+// doesn't make old information look new to recency scoring.
+func newest(sources []store.MemoryItem) time.Time {
+	var t time.Time
+	for _, s := range sources {
+		if s.CreatedAt.After(t) {
+			t = s.CreatedAt
+		}
+	}
+	return t
+}
+
+// FormatPlan renders plan as a diff: the memories each merge removes, then
+// the memory that replaces them.
+func FormatPlan(plan *Plan) string {
+	var sb strings.Builder
+	if len(plan.Merges) == 0 {
+		sb.WriteString(fmt.Sprintf("Nothing to consolidate among %d memories.\n", plan.Considered))
+		return sb.String()
+	}
+
+	for i, m := range plan.Merges {
+		sb.WriteString(fmt.Sprintf("Cluster %d: %d memories, similarity >= %.2f\n", i+1, len(m.Sources), m.MinSimilarity))
+		for _, src := range m.Sources {
+			sb.WriteString(fmt.Sprintf("- %s%s\n", src.Text, formatTags(src.Tags)))
+		}
+		sb.WriteString(fmt.Sprintf("+ %s%s\n\n", m.Text, formatTags(m.Tags)))
+	}
+	sb.WriteString(fmt.Sprintf("%d memories would be merged into %d (of %d considered).\n",
+		plan.Archived(), len(plan.Merges), plan.Considered))
+	return sb.String()
+}
+
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" [%s]", strings.Join(tags, ", "))
+}
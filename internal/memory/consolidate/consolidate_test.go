@@ -0,0 +1,214 @@
+package consolidate
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/memory/memutils"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/types"
+)
+
+var testModel = types.Model{Provider: "fake", ModelID: "fake-embed"}
+
+// mergeClient answers every merge prompt with a fixed JSON memory.
+type mergeClient struct {
+	response string
+	prompts  []string
+}
+
+func (c *mergeClient) ChatJSON(ctx context.Context, model types.Model, query string, schema client.JSONSchema) (string, error) {
+	c.prompts = append(c.prompts, query)
+	return c.response, nil
+}
+
+func (c *mergeClient) ChatStream(ctx context.Context, model types.Model, query string) (client.StreamResponse, error) {
+	panic("structured output should be used")
+}
+
+func (c *mergeClient) ChatStreamWithContext(ctx context.Context, model types.Model, systemContext, query string) (client.StreamResponse, error) {
+	panic("structured output should be used")
+}
+
+func (c *mergeClient) ListModels(ctx context.Context) ([]string, error) { return nil, nil }
+
+// fixedEmbedder embeds every text as the same vector.
+type fixedEmbedder struct{}
+
+func (fixedEmbedder) Embed(ctx context.Context, model types.Model, text string) ([]float32, error) {
+	return []float32{1, 0, 0}, nil
+}
+
+func (e fixedEmbedder) EmbedBatch(ctx context.Context, model types.Model, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i := range texts {
+		out[i], _ = e.Embed(ctx, model, texts[i])
+	}
+	return out, nil
+}
+
+func (fixedEmbedder) Dimensions(model types.Model) int { return 3 }
+
+func memory(id string, day int, vec ...float32) store.MemoryItem {
+	v := memutils.NormalizeVector(vec)
+	return store.MemoryItem{
+		ID: id, Text: "memory " + id, Source: store.SourceExplicit,
+		CreatedAt: time.Date(2025, 1, day, 0, 0, 0, 0, time.UTC),
+		Provider:  testModel.Provider, ModelID: testModel.ModelID, Dim: len(v), Embedding: v,
+	}
+}
+
+func ids(cluster []store.MemoryItem) string {
+	var out []string
+	for _, m := range cluster {
+		out = append(out, m.ID)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestCluster(t *testing.T) {
+	memories := []store.MemoryItem{
+		memory("c", 3, 1, 0.2, 0),
+		memory("a", 1, 1, 0, 0),
+		memory("b", 2, 1, 0.1, 0),
+		memory("d", 4, 1, 0.9, 0), // close to nothing but e
+		memory("e", 5, 1, 1, 0),
+		memory("f", 6, 0, 0, 1),
+	}
+
+	var got []string
+	for _, c := range Cluster(memories, 0.95, 8) {
+		got = append(got, ids(c))
+	}
+	if want := "a,b,c|d,e"; strings.Join(got, "|") != want {
+		t.Fatalf("got %q want %q", strings.Join(got, "|"), want)
+	}
+
+	// The size cap leaves the rest for a later cluster
+	got = nil
+	for _, c := range Cluster(memories, 0.95, 2) {
+		got = append(got, ids(c))
+	}
+	if want := "a,b|d,e"; strings.Join(got, "|") != want {
+		t.Fatalf("capped: got %q want %q", strings.Join(got, "|"), want)
+	}
+}
+
+func TestPlanAndApply(t *testing.T) {
+	s, err := store.NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	items := []store.MemoryItem{
+		memory("a", 1, 1, 0, 0),
+		memory("b", 2, 1, 0.1, 0),
+		memory("other", 3, 0, 1, 0),
+	}
+	items[0].Pinned = true
+	items[1].Importance = 0.8
+	chunk := memory("chunk", 4, 1, 0.05, 0)
+	chunk.Source, chunk.DocumentID, chunk.SourcePath, chunk.ContentHash = store.SourceDocument, "doc", "/docs/a.md", "h"
+	items = append(items, chunk)
+	for i := range items {
+		if err := s.SaveMemory(&items[i]); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	qc := &mergeClient{response: `{"text": "merged editor memory", "tags": ["editor", "Editor"]}`}
+	opts := Options{EmbeddingClient: fixedEmbedder{}, EmbeddingModel: testModel, QueryClient: qc}
+
+	plan, err := NewPlan(context.Background(), s, opts)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if plan.Considered != 3 || len(plan.Merges) != 1 || ids(plan.Merges[0].Sources) != "a,b" {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if len(qc.prompts) != 1 || !strings.Contains(qc.prompts[0], "memory a") {
+		t.Fatalf("expected one merge prompt listing the sources, got %q", qc.prompts)
+	}
+	diff := FormatPlan(plan)
+	for _, want := range []string{"- memory a\n", "- memory b\n", "+ merged editor memory [editor]\n", "2 memories would be merged into 1"} {
+		if !strings.Contains(diff, want) {
+			t.Fatalf("diff is missing %q:\n%s", want, diff)
+		}
+	}
+
+	// Planning changes nothing
+	if active, _ := s.GetMemories(store.MemoryFilter{}); len(active) != 4 {
+		t.Fatalf("dry run should not change memories, got %d active", len(active))
+	}
+
+	if err := Apply(context.Background(), s, plan, opts); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	active, _ := s.GetMemories(store.MemoryFilter{})
+	var merged *store.MemoryItem
+	for i := range active {
+		if active[i].Text == "merged editor memory" {
+			merged = &active[i]
+		}
+		if active[i].ID == "a" || active[i].ID == "b" {
+			t.Fatalf("source %s should be archived", active[i].ID)
+		}
+	}
+	if merged == nil || len(active) != 3 {
+		t.Fatalf("expected the merged memory among 3 active ones, got %+v", active)
+	}
+	if !merged.CreatedAt.Equal(items[1].CreatedAt) || merged.Source != store.SourceExplicit {
+		t.Fatalf("merged memory should keep the newest source's date and common source, got %+v", merged)
+	}
+	if !merged.Pinned || merged.Importance != 0.8 {
+		t.Fatalf("merged memory should stay pinned with the highest importance, got %+v", merged)
+	}
+
+	sources, err := s.GetMemorySources(merged.ID)
+	if err != nil || ids(sources) != "a,b" || sources[0].Status != store.StatusArchived {
+		t.Fatalf("expected archived sources a,b, got %+v (%v)", sources, err)
+	}
+}
+
+func TestApplySkipsChangedSources(t *testing.T) {
+	s, err := store.NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	items := []store.MemoryItem{memory("a", 1, 1, 0, 0), memory("b", 2, 1, 0.1, 0)}
+	for i := range items {
+		if err := s.SaveMemory(&items[i]); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+	opts := Options{EmbeddingClient: fixedEmbedder{}, EmbeddingModel: testModel,
+		QueryClient: &mergeClient{response: `{"text": "merged memory", "tags": []}`}}
+	plan, err := NewPlan(context.Background(), s, opts)
+	if err != nil || len(plan.Merges) != 1 {
+		t.Fatalf("plan: %+v, %v", plan, err)
+	}
+
+	// a is edited while the plan waits for confirmation
+	edited := items[0]
+	edited.Text = "memory a, corrected"
+	if err := s.UpdateMemory(&edited); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	err = Apply(context.Background(), s, plan, opts)
+	if err == nil || !strings.Contains(err.Error(), "cluster 1") {
+		t.Fatalf("expected cluster 1 to be skipped, got %v", err)
+	}
+	active, _ := s.GetMemories(store.MemoryFilter{})
+	if ids(active) != "a,b" && ids(active) != "b,a" {
+		t.Fatalf("expected a and b to stay active and nothing merged, got %s", ids(active))
+	}
+}
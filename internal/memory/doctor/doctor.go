@@ -0,0 +1,261 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/memory/memutils"
+	"github.com/austiecodes/gomor/internal/memory/retrieval"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/types"
+)
+
+// normTolerance is how far a stored vector's L2 norm may drift from 1.
+const normTolerance = 1e-3
+
+// Issue kinds reported for individual memories
+const (
+	IssueModelMismatch = "model_mismatch" // embedded with a different provider/model than configured
+	IssueDimMismatch   = "dim_mismatch"   // dim column doesn't match the stored vector length
+	IssueNotNormalized = "not_normalized" // vector is not unit length
+	IssueZeroVector    = "zero_vector"    // vector is empty or all zeros
+)
+
+// Options configures a doctor run.
+type Options struct {
+	// Fix repairs what can be repaired instead of only reporting.
+	Fix bool
+	// EmbeddingModel is the configured embedding model rows are compared against.
+	EmbeddingModel types.Model
+	// EmbeddingClient is used to re-embed mismatched rows when fixing. May be nil.
+	EmbeddingClient client.EmbeddingClient
+}
+
+// MemoryIssue is a problem found on a single memory row.
+type MemoryIssue struct {
+	MemoryID string
+	Kind     string
+	Detail   string
+	Fixed    bool
+}
+
+// Report summarizes the result of a doctor run.
+type Report struct {
+	Integrity    []string
+	FTS          store.FTSStatus
+	FTSRebuilt   bool
+	Memories     int
+	MemoryIssues []MemoryIssue
+	FixErrors    []string
+}
+
+// Healthy reports whether no unresolved problems remain.
+func (r *Report) Healthy() bool {
+	if len(r.Integrity) > 0 || len(r.FixErrors) > 0 {
+		return false
+	}
+	if !r.FTS.InSync() && !r.FTSRebuilt {
+		return false
+	}
+	for _, issue := range r.MemoryIssues {
+		if !issue.Fixed {
+			return false
+		}
+	}
+	return true
+}
+
+// Run checks the store and, with opts.Fix, repairs what it can.
+func Run(ctx context.Context, s *store.Store, opts Options) (*Report, error) {
+	if opts.Fix {
+		lock, err := s.AcquireMaintenanceLock(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer lock.Release()
+	}
+
+	report := &Report{}
+
+	// 1. SQLite integrity check. Nothing we can repair here, but it tells the
+	// user to restore from backup before trusting anything else.
+	problems, err := s.IntegrityCheck()
+	if err != nil {
+		return nil, err
+	}
+	report.Integrity = problems
+
+	// 2. FTS index drift
+	report.FTS, err = s.CheckMemoriesFTS()
+	if err != nil {
+		return nil, err
+	}
+	if !report.FTS.InSync() && opts.Fix {
+		if err := s.RebuildMemoriesFTS(); err != nil {
+			report.FixErrors = append(report.FixErrors, err.Error())
+		} else {
+			report.FTSRebuilt = true
+		}
+	}
+
+	// 3. Per-row embedding checks
+	memories, err := s.GetAllMemories()
+	if err != nil {
+		return nil, err
+	}
+	report.Memories = len(memories)
+
+	var reembed []store.MemoryItem
+	var reembedIssues []int
+	for _, m := range memories {
+		issues := checkMemory(m, opts.EmbeddingModel)
+		if len(issues) == 0 {
+			continue
+		}
+
+		needsReembed := false
+		for _, issue := range issues {
+			if issue.Kind != IssueNotNormalized {
+				needsReembed = true
+			}
+		}
+
+		start := len(report.MemoryIssues)
+		report.MemoryIssues = append(report.MemoryIssues, issues...)
+		if !opts.Fix {
+			continue
+		}
+
+		if needsReembed {
+			reembed = append(reembed, m)
+			for i := start; i < len(report.MemoryIssues); i++ {
+				reembedIssues = append(reembedIssues, i)
+			}
+			continue
+		}
+
+		// Only the norm is off: normalize in place, no API call needed.
+		normalized := memutils.NormalizeVector(m.Embedding)
+		if err := s.UpdateMemoryEmbedding(m.ID, normalized, m.ModelID, len(normalized), m.Provider); err != nil {
+			report.FixErrors = append(report.FixErrors, fmt.Sprintf("%s: %v", m.ID, err))
+			continue
+		}
+		for i := start; i < len(report.MemoryIssues); i++ {
+			report.MemoryIssues[i].Fixed = true
+		}
+	}
+
+	if len(reembed) > 0 {
+		switch {
+		case opts.EmbeddingClient == nil:
+			report.FixErrors = append(report.FixErrors,
+				fmt.Sprintf("%d memories need re-embedding but no embedding client is available", len(reembed)))
+		default:
+			if err := retrieval.ReindexMemoryItems(ctx, s, opts.EmbeddingClient, opts.EmbeddingModel, reembed); err != nil {
+				report.FixErrors = append(report.FixErrors, err.Error())
+			} else {
+				for _, i := range reembedIssues {
+					report.MemoryIssues[i].Fixed = true
+				}
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// checkMemory returns the problems found on a single memory.
+func checkMemory(m store.MemoryItem, model types.Model) []MemoryIssue {
+	var issues []MemoryIssue
+
+	if model.ModelID != "" && (m.Provider != model.Provider || m.ModelID != model.ModelID) {
+		issues = append(issues, MemoryIssue{
+			MemoryID: m.ID,
+			Kind:     IssueModelMismatch,
+			Detail:   fmt.Sprintf("embedded with %s/%s, configured %s/%s", m.Provider, m.ModelID, model.Provider, model.ModelID),
+		})
+	}
+
+	if m.Dim != len(m.Embedding) {
+		issues = append(issues, MemoryIssue{
+			MemoryID: m.ID,
+			Kind:     IssueDimMismatch,
+			Detail:   fmt.Sprintf("dim column is %d but vector has %d values", m.Dim, len(m.Embedding)),
+		})
+	}
+
+	norm := vectorNorm(m.Embedding)
+	switch {
+	case norm == 0:
+		issues = append(issues, MemoryIssue{MemoryID: m.ID, Kind: IssueZeroVector, Detail: "vector is empty or all zeros"})
+	case math.Abs(norm-1) > normTolerance:
+		issues = append(issues, MemoryIssue{
+			MemoryID: m.ID,
+			Kind:     IssueNotNormalized,
+			Detail:   fmt.Sprintf("vector norm is %.4f", norm),
+		})
+	}
+
+	return issues
+}
+
+func vectorNorm(v []float32) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	return math.Sqrt(sum)
+}
+
+// FormatReport renders a report as human-readable text.
+func FormatReport(r *Report, fix bool) string {
+	var sb strings.Builder
+
+	if len(r.Integrity) == 0 {
+		sb.WriteString("[ok]   database integrity\n")
+	} else {
+		sb.WriteString("[FAIL] database integrity (restore from a backup):\n")
+		for _, p := range r.Integrity {
+			sb.WriteString(fmt.Sprintf("         %s\n", p))
+		}
+	}
+
+	switch {
+	case r.FTS.InSync():
+		sb.WriteString(fmt.Sprintf("[ok]   full-text index (%d memories)\n", r.FTS.Memories))
+	case r.FTSRebuilt:
+		sb.WriteString(fmt.Sprintf("[fixed] full-text index rebuilt (%d missing, %d orphaned)\n", r.FTS.Missing, r.FTS.Orphaned))
+	default:
+		sb.WriteString(fmt.Sprintf("[FAIL] full-text index out of sync: %d memories, %d indexed, %d missing, %d orphaned",
+			r.FTS.Memories, r.FTS.Indexed, r.FTS.Missing, r.FTS.Orphaned))
+		if r.FTS.Corrupt {
+			sb.WriteString(", stale entries")
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(r.MemoryIssues) == 0 {
+		sb.WriteString(fmt.Sprintf("[ok]   embeddings (%d memories)\n", r.Memories))
+	} else {
+		for _, issue := range r.MemoryIssues {
+			status := "[fixed]"
+			if !issue.Fixed {
+				status = "[FAIL]"
+			}
+			sb.WriteString(fmt.Sprintf("%s %s %s: %s\n", status, issue.MemoryID, issue.Kind, issue.Detail))
+		}
+	}
+
+	for _, e := range r.FixErrors {
+		sb.WriteString(fmt.Sprintf("[error] %s\n", e))
+	}
+
+	if !fix && !r.Healthy() {
+		sb.WriteString("\nRun 'gomor doctor --fix' to repair what can be repaired.\n")
+	}
+
+	return sb.String()
+}
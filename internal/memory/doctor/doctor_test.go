@@ -0,0 +1,123 @@
+package doctor
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/types"
+)
+
+// fakeEmbeddingClient returns a fixed, non-normalized vector.
+type fakeEmbeddingClient struct{}
+
+func (f *fakeEmbeddingClient) Embed(ctx context.Context, model types.Model, text string) ([]float32, error) {
+	return []float32{3, 4, 0}, nil
+}
+
+func (f *fakeEmbeddingClient) EmbedBatch(ctx context.Context, model types.Model, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i := range texts {
+		vectors[i], _ = f.Embed(ctx, model, texts[i])
+	}
+	return vectors, nil
+}
+
+func (f *fakeEmbeddingClient) Dimensions(model types.Model) int { return 3 }
+
+func TestDoctor_DetectsAndFixes(t *testing.T) {
+	ctx := context.Background()
+	model := types.Model{Provider: "fake", ModelID: "fake-embed"}
+
+	dbPath := filepath.Join(t.TempDir(), "memory.db")
+	s, err := store.NewStoreWithPath(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	healthy := &store.MemoryItem{Text: "healthy memory", Source: store.SourceExplicit,
+		Provider: model.Provider, ModelID: model.ModelID, Dim: 3, Embedding: []float32{1, 0, 0}}
+	unnormalized := &store.MemoryItem{Text: "unnormalized memory", Source: store.SourceExplicit,
+		Provider: model.Provider, ModelID: model.ModelID, Dim: 3, Embedding: []float32{2, 0, 0}}
+	oldModel := &store.MemoryItem{Text: "old model memory", Source: store.SourceExplicit,
+		Provider: "fake", ModelID: "old-embed", Dim: 2, Embedding: []float32{0, 1}}
+	for _, item := range []*store.MemoryItem{healthy, unnormalized, oldModel} {
+		if err := s.SaveMemory(item); err != nil {
+			t.Fatalf("save memory: %v", err)
+		}
+	}
+
+	// Drop one memory from the FTS index behind the store's back.
+	raw, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open raw db: %v", err)
+	}
+	_, err = raw.Exec(`INSERT INTO memories_fts(memories_fts, rowid, text)
+		SELECT 'delete', rowid, text FROM memories WHERE id = ?`, healthy.ID)
+	raw.Close()
+	if err != nil {
+		t.Fatalf("corrupt fts: %v", err)
+	}
+
+	report, err := Run(ctx, s, Options{EmbeddingModel: model})
+	if err != nil {
+		t.Fatalf("run doctor: %v", err)
+	}
+	if report.Healthy() {
+		t.Fatalf("expected problems, got healthy report:\n%s", FormatReport(report, false))
+	}
+	if report.FTS.InSync() || report.FTS.Missing != 1 {
+		t.Errorf("expected 1 missing FTS entry, got %+v", report.FTS)
+	}
+
+	kinds := make(map[string]string)
+	for _, issue := range report.MemoryIssues {
+		kinds[issue.MemoryID+"/"+issue.Kind] = issue.Detail
+	}
+	for _, want := range []string{
+		unnormalized.ID + "/" + IssueNotNormalized,
+		oldModel.ID + "/" + IssueModelMismatch,
+	} {
+		if _, ok := kinds[want]; !ok {
+			t.Errorf("missing issue %s in %v", want, kinds)
+		}
+	}
+	if len(report.MemoryIssues) != 2 {
+		t.Errorf("expected 2 issues, got %d: %v", len(report.MemoryIssues), kinds)
+	}
+
+	report, err = Run(ctx, s, Options{Fix: true, EmbeddingModel: model, EmbeddingClient: &fakeEmbeddingClient{}})
+	if err != nil {
+		t.Fatalf("run doctor --fix: %v", err)
+	}
+	if !report.Healthy() {
+		t.Fatalf("expected fix to resolve everything:\n%s", FormatReport(report, true))
+	}
+
+	report, err = Run(ctx, s, Options{EmbeddingModel: model})
+	if err != nil {
+		t.Fatalf("rerun doctor: %v", err)
+	}
+	if !report.Healthy() {
+		t.Fatalf("expected healthy store after fix:\n%s", FormatReport(report, false))
+	}
+
+	memories, err := s.GetAllMemories()
+	if err != nil {
+		t.Fatalf("get memories: %v", err)
+	}
+	for _, m := range memories {
+		if math.Abs(vectorNorm(m.Embedding)-1) > normTolerance {
+			t.Errorf("memory %s not normalized after fix: %v", m.ID, m.Embedding)
+		}
+		if m.ModelID != model.ModelID || m.Dim != len(m.Embedding) {
+			t.Errorf("memory %s has model %s dim %d after fix", m.ID, m.ModelID, m.Dim)
+		}
+	}
+}
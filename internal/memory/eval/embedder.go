@@ -0,0 +1,58 @@
+package eval
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"unicode"
+
+	"github.com/austiecodes/gomor/internal/memory/memutils"
+	"github.com/austiecodes/gomor/internal/types"
+)
+
+// HashModel is the model name recorded on memories embedded by HashEmbedder.
+var HashModel = types.Model{Provider: "eval", ModelID: "hash"}
+
+// HashEmbedder is a deterministic, offline embedding client. It hashes each
+// lowercased word into one of Dim buckets, so texts that share words are
+// similar. It is no substitute for a real model, but it makes evaluation runs
+// reproducible and free.
+type HashEmbedder struct {
+	Dim int
+}
+
+// NewHashEmbedder returns a HashEmbedder with 256 dimensions.
+func NewHashEmbedder() *HashEmbedder {
+	return &HashEmbedder{Dim: 256}
+}
+
+func (e *HashEmbedder) Embed(ctx context.Context, model types.Model, text string) ([]float32, error) {
+	vec := make([]float32, e.Dim)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		h := fnv.New32a()
+		h.Write([]byte(w))
+		sum := h.Sum32()
+		// The top bit picks the sign so that collisions tend to cancel out
+		if sum&(1<<31) != 0 {
+			vec[int(sum%uint32(e.Dim))] -= 1
+		} else {
+			vec[int(sum%uint32(e.Dim))] += 1
+		}
+	}
+	return memutils.NormalizeVector(vec), nil
+}
+
+func (e *HashEmbedder) EmbedBatch(ctx context.Context, model types.Model, texts []string) ([][]float32, error) {
+	vecs := make([][]float32, len(texts))
+	for i, text := range texts {
+		vecs[i], _ = e.Embed(ctx, model, text)
+	}
+	return vecs, nil
+}
+
+func (e *HashEmbedder) Dimensions(model types.Model) int {
+	return e.Dim
+}
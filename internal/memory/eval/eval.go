@@ -0,0 +1,342 @@
+package eval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/memory/memtypes"
+	"github.com/austiecodes/gomor/internal/memory/memutils"
+	"github.com/austiecodes/gomor/internal/memory/retrieval"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// DefaultK is the default cutoff for recall@k and nDCG@k.
+const DefaultK = 5
+
+// Dataset is a fixed set of memories and queries with the memories each query
+// is expected to find, plus the configurations to compare.
+type Dataset struct {
+	Memories []Memory        `json:"memories"`
+	Queries  []Query         `json:"queries"`
+	Configs  []Configuration `json:"configs,omitempty"`
+}
+
+// Memory is a memory to load into the evaluation store.
+type Memory struct {
+	ID        string                `json:"id"`
+	Text      string                `json:"text"`
+	Tags      []string              `json:"tags,omitempty"`
+	Source    memtypes.MemorySource `json:"source,omitempty"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+// Query is a search query and the IDs of the memories it should return.
+type Query struct {
+	Query    string   `json:"query"`
+	Expected []string `json:"expected"`
+}
+
+// Configuration names a set of memory settings to evaluate. Memory uses the
+// keys of the "memory" section of settings.json and overrides only the keys
+// it sets, e.g. {"fusion_strategy": "weighted", "vector_weight": 0.7}.
+type Configuration struct {
+	Name   string          `json:"name"`
+	Memory json.RawMessage `json:"memory,omitempty"`
+}
+
+// LoadDataset reads and validates a dataset file.
+func LoadDataset(path string) (*Dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+
+	var ds Dataset
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&ds); err != nil {
+		return nil, fmt.Errorf("failed to parse dataset: %w", err)
+	}
+	if err := ds.Validate(); err != nil {
+		return nil, err
+	}
+	return &ds, nil
+}
+
+// Validate checks that IDs are unique and every expected ID exists.
+func (ds *Dataset) Validate() error {
+	ids := make(map[string]bool, len(ds.Memories))
+	for i, m := range ds.Memories {
+		if m.ID == "" || strings.TrimSpace(m.Text) == "" {
+			return fmt.Errorf("memory %d: id and text are required", i+1)
+		}
+		if ids[m.ID] {
+			return fmt.Errorf("memory %q: duplicate id", m.ID)
+		}
+		ids[m.ID] = true
+	}
+
+	if len(ds.Queries) == 0 {
+		return fmt.Errorf("dataset has no queries")
+	}
+	for i, q := range ds.Queries {
+		if strings.TrimSpace(q.Query) == "" || len(q.Expected) == 0 {
+			return fmt.Errorf("query %d: query and expected are required", i+1)
+		}
+		for _, id := range q.Expected {
+			if !ids[id] {
+				return fmt.Errorf("query %d: expected memory %q is not in the dataset", i+1, id)
+			}
+		}
+	}
+
+	names := make(map[string]bool, len(ds.Configs))
+	for i, c := range ds.Configs {
+		if c.Name == "" {
+			return fmt.Errorf("config %d: name is required", i+1)
+		}
+		if names[c.Name] {
+			return fmt.Errorf("config %q: duplicate name", c.Name)
+		}
+		names[c.Name] = true
+	}
+	return nil
+}
+
+// Options configures an evaluation run.
+type Options struct {
+	// K is the cutoff for recall@k and nDCG@k; 0 uses DefaultK.
+	K int
+	// EmbeddingClient embeds memories and queries; nil uses a HashEmbedder,
+	// which keeps the run offline and deterministic.
+	EmbeddingClient client.EmbeddingClient
+	EmbeddingModel  types.Model
+	// QueryClient is used for query transformation and reranking. May be nil.
+	QueryClient client.QueryClient
+	ToolModel   types.Model
+}
+
+// Report holds the metrics of every evaluated configuration.
+type Report struct {
+	K       int
+	Queries int
+	Configs []ConfigResult
+}
+
+// ConfigResult holds the metrics of one configuration, averaged over all queries.
+type ConfigResult struct {
+	Name    string
+	Recall  float64 // mean recall@k
+	MRR     float64
+	NDCG    float64 // mean nDCG@k
+	Latency time.Duration
+	Queries []QueryResult
+}
+
+// QueryResult records what one query returned under a configuration.
+type QueryResult struct {
+	Query    string
+	Expected []string
+	Returned []string
+	Recall   float64
+	RR       float64
+	NDCG     float64
+	Err      string
+}
+
+// Run loads the dataset into a temporary store and retrieves every query under
+// each configuration, layered over base. Without configurations in the dataset,
+// base itself is evaluated as "current".
+func Run(ctx context.Context, ds *Dataset, base utils.MemoryConfig, opts Options) (*Report, error) {
+	if opts.K <= 0 {
+		opts.K = DefaultK
+	}
+	if opts.EmbeddingClient == nil {
+		opts.EmbeddingClient = NewHashEmbedder()
+		opts.EmbeddingModel = HashModel
+	}
+
+	configs := ds.Configs
+	if len(configs) == 0 {
+		configs = []Configuration{{Name: "current"}}
+	}
+	memConfigs := make([]utils.MemoryConfig, len(configs))
+	for i, c := range configs {
+		cfg, err := applyConfig(base, c)
+		if err != nil {
+			return nil, err
+		}
+		memConfigs[i] = cfg
+	}
+
+	dir, err := os.MkdirTemp("", "gomor-eval-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create eval directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.NewStoreWithPath(filepath.Join(dir, "memory.db"))
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+
+	if err := loadMemories(ctx, s, ds.Memories, opts); err != nil {
+		return nil, err
+	}
+
+	report := &Report{K: opts.K, Queries: len(ds.Queries)}
+	for i, c := range configs {
+		r := retrieval.NewRetriever(s, opts.EmbeddingClient, opts.QueryClient, opts.EmbeddingModel, opts.ToolModel, memConfigs[i])
+		report.Configs = append(report.Configs, evaluate(ctx, c.Name, r, ds.Queries, opts.K))
+	}
+	return report, nil
+}
+
+// applyConfig overlays a configuration's memory settings on base.
+func applyConfig(base utils.MemoryConfig, c Configuration) (utils.MemoryConfig, error) {
+	cfg := base
+	cfg.RecencyOverrides = maps.Clone(base.RecencyOverrides) // don't let the overlay merge into base
+	if len(c.Memory) > 0 {
+		if err := json.Unmarshal(c.Memory, &cfg); err != nil {
+			return cfg, fmt.Errorf("config %q: %w", c.Name, err)
+		}
+	}
+	return cfg, nil
+}
+
+func loadMemories(ctx context.Context, s *store.Store, memories []Memory, opts Options) error {
+	if len(memories) == 0 {
+		return nil
+	}
+
+	texts := make([]string, len(memories))
+	for i, m := range memories {
+		texts[i] = m.Text
+	}
+	embeddings, err := opts.EmbeddingClient.EmbedBatch(ctx, opts.EmbeddingModel, texts)
+	if err != nil {
+		return fmt.Errorf("failed to embed memories: %w", err)
+	}
+	if len(embeddings) != len(memories) {
+		return fmt.Errorf("failed to embed memories: got %d embeddings for %d memories", len(embeddings), len(memories))
+	}
+
+	for i, m := range memories {
+		source := m.Source
+		if source == "" {
+			source = memtypes.SourceExplicit
+		}
+		vec := memutils.NormalizeVector(embeddings[i])
+		item := &memtypes.MemoryItem{
+			ID:        m.ID,
+			Text:      m.Text,
+			Tags:      m.Tags,
+			Source:    source,
+			CreatedAt: m.CreatedAt,
+			Provider:  opts.EmbeddingModel.Provider,
+			ModelID:   opts.EmbeddingModel.ModelID,
+			Dim:       len(vec),
+			Embedding: vec,
+		}
+		if err := s.SaveMemory(item); err != nil {
+			return fmt.Errorf("memory %q: %w", m.ID, err)
+		}
+	}
+	return nil
+}
+
+func evaluate(ctx context.Context, name string, r *retrieval.Retriever, queries []Query, k int) ConfigResult {
+	result := ConfigResult{Name: name}
+	var elapsed time.Duration
+
+	for _, q := range queries {
+		qr := QueryResult{Query: q.Query, Expected: q.Expected}
+		relevant := make(map[string]bool, len(q.Expected))
+		for _, id := range q.Expected {
+			relevant[id] = true
+		}
+
+		start := time.Now()
+		resp, err := r.Retrieve(ctx, q.Query)
+		elapsed += time.Since(start)
+		if err != nil {
+			// A failed query scores 0 rather than aborting the comparison
+			qr.Err = err.Error()
+		} else {
+			for _, res := range resp.Results {
+				qr.Returned = append(qr.Returned, res.Item.ID)
+			}
+			qr.Recall = RecallAt(qr.Returned, relevant, k)
+			qr.RR = ReciprocalRank(qr.Returned, relevant)
+			qr.NDCG = NDCGAt(qr.Returned, relevant, k)
+		}
+
+		result.Recall += qr.Recall
+		result.MRR += qr.RR
+		result.NDCG += qr.NDCG
+		result.Queries = append(result.Queries, qr)
+	}
+
+	n := float64(len(queries))
+	result.Recall /= n
+	result.MRR /= n
+	result.NDCG /= n
+	result.Latency = elapsed / time.Duration(len(queries))
+	return result
+}
+
+// FormatReport renders the configurations side by side. With verbose, queries
+// that missed any expected memory are listed per configuration.
+func FormatReport(report *Report, verbose bool) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Evaluated %d queries, k=%d\n\n", report.Queries, report.K))
+
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "CONFIG\tRECALL@%d\tMRR\tNDCG@%d\tAVG LATENCY\n", report.K, report.K)
+	for _, c := range report.Configs {
+		fmt.Fprintf(tw, "%s\t%.3f\t%.3f\t%.3f\t%s\n", c.Name, c.Recall, c.MRR, c.NDCG, c.Latency.Round(time.Microsecond))
+	}
+	tw.Flush()
+
+	if !verbose {
+		return sb.String()
+	}
+	for _, c := range report.Configs {
+		var misses []string
+		for _, q := range c.Queries {
+			if q.Err != "" {
+				misses = append(misses, fmt.Sprintf("  %q: error: %s\n", q.Query, q.Err))
+			} else if q.Recall < 1 {
+				misses = append(misses, fmt.Sprintf("  %q: expected %s, got %s\n",
+					q.Query, strings.Join(q.Expected, ","), formatIDs(q.Returned, report.K)))
+			}
+		}
+		if len(misses) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n%s missed:\n", c.Name))
+		for _, m := range misses {
+			sb.WriteString(m)
+		}
+	}
+	return sb.String()
+}
+
+func formatIDs(ids []string, k int) string {
+	if len(ids) == 0 {
+		return "nothing"
+	}
+	return strings.Join(ids[:min(k, len(ids))], ",")
+}
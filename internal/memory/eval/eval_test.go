@@ -0,0 +1,132 @@
+package eval
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestMetrics(t *testing.T) {
+	ranked := []string{"x", "a", "y", "b"}
+	relevant := map[string]bool{"a": true, "b": true}
+
+	if got := RecallAt(ranked, relevant, 2); !approxEqual(got, 0.5) {
+		t.Errorf("recall@2: got %f", got)
+	}
+	if got := RecallAt(ranked, relevant, 10); !approxEqual(got, 1) {
+		t.Errorf("recall@10: got %f", got)
+	}
+	if got := ReciprocalRank(ranked, relevant); !approxEqual(got, 0.5) {
+		t.Errorf("rr: got %f", got)
+	}
+	if got := ReciprocalRank([]string{"x"}, relevant); got != 0 {
+		t.Errorf("rr without hits: got %f", got)
+	}
+
+	// Hits at ranks 2 and 4 against an ideal of ranks 1 and 2
+	want := (1/math.Log2(3) + 1/math.Log2(5)) / (1 + 1/math.Log2(3))
+	if got := NDCGAt(ranked, relevant, 4); !approxEqual(got, want) {
+		t.Errorf("ndcg@4: got %f want %f", got, want)
+	}
+	if got := NDCGAt([]string{"a", "b"}, relevant, 5); !approxEqual(got, 1) {
+		t.Errorf("perfect ndcg: got %f", got)
+	}
+}
+
+func TestDatasetValidate(t *testing.T) {
+	tests := map[string]*Dataset{
+		"duplicate id": {
+			Memories: []Memory{{ID: "a", Text: "x"}, {ID: "a", Text: "y"}},
+			Queries:  []Query{{Query: "q", Expected: []string{"a"}}},
+		},
+		"not in the dataset": {
+			Memories: []Memory{{ID: "a", Text: "x"}},
+			Queries:  []Query{{Query: "q", Expected: []string{"b"}}},
+		},
+		"no queries": {
+			Memories: []Memory{{ID: "a", Text: "x"}},
+		},
+		"duplicate name": {
+			Memories: []Memory{{ID: "a", Text: "x"}},
+			Queries:  []Query{{Query: "q", Expected: []string{"a"}}},
+			Configs:  []Configuration{{Name: "c"}, {Name: "c"}},
+		},
+	}
+	for want, ds := range tests {
+		if err := ds.Validate(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("got error %v, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestRun_Sample(t *testing.T) {
+	ds, err := LoadDataset(filepath.Join("testdata", "sample.json"))
+	if err != nil {
+		t.Fatalf("load dataset: %v", err)
+	}
+
+	base := utils.DefaultConfig().Memory
+	report, err := Run(context.Background(), ds, base, Options{K: 3})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(report.Configs) != 4 || report.Queries != len(ds.Queries) {
+		t.Fatalf("unexpected report shape: %+v", report)
+	}
+
+	byName := make(map[string]ConfigResult)
+	for _, c := range report.Configs {
+		byName[c.Name] = c
+		if len(c.Queries) != len(ds.Queries) {
+			t.Errorf("%s: expected a result per query", c.Name)
+		}
+		if c.Recall < 0 || c.Recall > 1 || c.MRR < 0 || c.MRR > 1 || c.NDCG < 0 || c.NDCG > 1 {
+			t.Errorf("%s: metrics out of range: %+v", c.Name, c)
+		}
+	}
+	if byName["rrf"].MRR < 0.5 {
+		t.Errorf("rrf should find most expected memories, got %+v", byName["rrf"])
+	}
+
+	// The hash embedder makes runs reproducible
+	again, err := Run(context.Background(), ds, base, Options{K: 3})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	for i, c := range again.Configs {
+		prev := report.Configs[i]
+		if c.Recall != prev.Recall || c.MRR != prev.MRR || c.NDCG != prev.NDCG {
+			t.Errorf("%s: metrics changed between runs: %+v vs %+v", c.Name, c, prev)
+		}
+	}
+
+	out := FormatReport(report, true)
+	for _, want := range []string{"RECALL@3", "NDCG@3", "rrf", "weighted", "legacy", "strict"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+	t.Log("\n" + out)
+}
+
+func TestApplyConfig(t *testing.T) {
+	base := utils.DefaultConfig().Memory
+	cfg, err := applyConfig(base, Configuration{Name: "c", Memory: []byte(`{"rrf_k": 20, "recency_overrides": {"tag:go": 30}}`)})
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if cfg.RRFK != 20 || cfg.MemoryTopK != base.MemoryTopK {
+		t.Fatalf("expected only rrf_k to change, got %+v", cfg)
+	}
+	if _, ok := base.RecencyOverrides["tag:go"]; ok {
+		t.Fatalf("overlay must not modify the base config")
+	}
+}
@@ -0,0 +1,48 @@
+package eval
+
+import "math"
+
+// RecallAt returns the fraction of relevant IDs found in the first k ranked IDs.
+func RecallAt(ranked []string, relevant map[string]bool, k int) float64 {
+	if len(relevant) == 0 {
+		return 0
+	}
+	var found int
+	for _, id := range ranked[:min(k, len(ranked))] {
+		if relevant[id] {
+			found++
+		}
+	}
+	return float64(found) / float64(len(relevant))
+}
+
+// ReciprocalRank returns 1/rank of the first relevant ID, or 0 if none was returned.
+func ReciprocalRank(ranked []string, relevant map[string]bool) float64 {
+	for i, id := range ranked {
+		if relevant[id] {
+			return 1 / float64(i+1)
+		}
+	}
+	return 0
+}
+
+// NDCGAt returns the normalized discounted cumulative gain of the first k
+// ranked IDs with binary relevance: 1 when every relevant ID is ranked ahead
+// of every irrelevant one.
+func NDCGAt(ranked []string, relevant map[string]bool, k int) float64 {
+	var dcg float64
+	for i, id := range ranked[:min(k, len(ranked))] {
+		if relevant[id] {
+			dcg += 1 / math.Log2(float64(i+2))
+		}
+	}
+
+	var ideal float64
+	for i := range min(k, len(relevant)) {
+		ideal += 1 / math.Log2(float64(i+2))
+	}
+	if ideal == 0 {
+		return 0
+	}
+	return dcg / ideal
+}
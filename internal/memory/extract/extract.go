@@ -0,0 +1,272 @@
+// Package extract asks the tool model for durable facts and preferences in a
+// conversation transcript and saves them as memories pending review.
+package extract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/memory/memutils"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// maxTranscriptChars bounds the transcript sent to the tool model. Longer
+// transcripts keep their most recent part.
+const maxTranscriptChars = 24000
+
+// extractSchema is the structured response requested from tool_model.
+var extractSchema = client.JSONSchema{
+	Name:        "extracted_memories",
+	Description: "Durable facts and preferences about the user found in a conversation",
+	Schema: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"memories": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"text":       map[string]any{"type": "string"},
+						"tags":       map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+						"confidence": map[string]any{"type": "number"},
+					},
+					"required":             []string{"text", "tags", "confidence"},
+					"additionalProperties": false,
+				},
+			},
+		},
+		"required":             []string{"memories"},
+		"additionalProperties": false,
+	},
+}
+
+// Candidate is a memory proposed by the tool model.
+type Candidate struct {
+	Text       string   `json:"text"`
+	Tags       []string `json:"tags,omitempty"`
+	Confidence float64  `json:"confidence"`
+}
+
+// Duplicate is a candidate that matches a memory already stored, or one
+// proposed earlier in the same transcript.
+type Duplicate struct {
+	Candidate
+	MemoryID   string  `json:"memory_id"`
+	MemoryText string  `json:"memory_text"`
+	Similarity float64 `json:"similarity"`
+}
+
+// Result describes what an extraction found and saved.
+type Result struct {
+	// Saved memories have SourceExtracted and wait for review.
+	Saved         []store.MemoryItem `json:"saved"`
+	Duplicates    []Duplicate        `json:"duplicates,omitempty"`
+	LowConfidence []Candidate        `json:"low_confidence,omitempty"`
+}
+
+// Extractor turns transcripts into memories pending review.
+type Extractor struct {
+	store           *store.Store
+	embeddingClient client.EmbeddingClient
+	queryClient     client.QueryClient
+	embeddingModel  types.Model
+	toolModel       types.Model
+	config          utils.MemoryConfig
+}
+
+// NewExtractor creates a new extractor.
+func NewExtractor(
+	s *store.Store,
+	embeddingClient client.EmbeddingClient,
+	queryClient client.QueryClient,
+	embeddingModel types.Model,
+	toolModel types.Model,
+	config utils.MemoryConfig,
+) *Extractor {
+	return &Extractor{
+		store:           s,
+		embeddingClient: embeddingClient,
+		queryClient:     queryClient,
+		embeddingModel:  embeddingModel,
+		toolModel:       toolModel,
+		config:          config,
+	}
+}
+
+// Extract asks the tool model for memories in transcript, drops those below
+// the configured confidence and those that duplicate an existing memory, and
+// saves the rest with status pending, so retrieval ignores them until they
+// are approved.
+func (e *Extractor) Extract(ctx context.Context, transcript string) (*Result, error) {
+	transcript = strings.TrimSpace(transcript)
+	if transcript == "" {
+		return nil, fmt.Errorf("transcript is empty")
+	}
+	if e.queryClient == nil {
+		return nil, fmt.Errorf("tool model not configured. Run 'gomor set' to configure")
+	}
+
+	candidates, err := e.candidates(ctx, transcript)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	var confident []Candidate
+	for _, c := range candidates {
+		if c.Confidence < e.config.ExtractMinConfidence {
+			result.LowConfidence = append(result.LowConfidence, c)
+			continue
+		}
+		confident = append(confident, c)
+	}
+	if len(confident) == 0 {
+		return result, nil
+	}
+
+	texts := make([]string, len(confident))
+	for i, c := range confident {
+		texts[i] = c.Text
+	}
+	embeddings, err := e.embeddingClient.EmbedBatch(ctx, e.embeddingModel, texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed extracted memories: %w", err)
+	}
+	if len(embeddings) != len(confident) {
+		return nil, fmt.Errorf("failed to embed extracted memories: got %d embeddings for %d memories", len(embeddings), len(confident))
+	}
+
+	// Pending memories count too, so extracting the same conversation twice
+	// doesn't queue everything again
+	existing, err := e.store.GetAllMemories()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, c := range confident {
+		embedding := memutils.NormalizeVector(embeddings[i])
+		if dup, ok := e.findDuplicate(c, embedding, existing); ok {
+			result.Duplicates = append(result.Duplicates, dup)
+			continue
+		}
+
+		item := store.MemoryItem{
+			Text:       c.Text,
+			Tags:       c.Tags,
+			Source:     store.SourceExtracted,
+			Status:     store.StatusPending,
+			Confidence: c.Confidence,
+			Provider:   e.embeddingModel.Provider,
+			ModelID:    e.embeddingModel.ModelID,
+			Dim:        len(embedding),
+			Embedding:  embedding,
+		}
+		if err := e.store.SaveMemory(&item); err != nil {
+			return nil, err
+		}
+		result.Saved = append(result.Saved, item)
+		existing = append(existing, item)
+	}
+
+	return result, nil
+}
+
+// findDuplicate returns the stored memory most similar to a candidate, if it
+// is at least as similar as the duplicate threshold. The same text, ignoring
+// case and spacing, is a duplicate whatever model embedded it.
+func (e *Extractor) findDuplicate(c Candidate, embedding []float32, existing []store.MemoryItem) (Duplicate, bool) {
+	best := Duplicate{Candidate: c}
+	found := false
+	for _, m := range existing {
+		var similarity float64
+		switch {
+		case normalizeText(m.Text) == normalizeText(c.Text):
+			similarity = 1
+		case m.Provider == e.embeddingModel.Provider && m.ModelID == e.embeddingModel.ModelID && len(m.Embedding) == len(embedding):
+			similarity = memutils.DotProduct(embedding, m.Embedding)
+		default:
+			continue
+		}
+		if similarity >= e.config.ExtractDuplicateSimilarity && (!found || similarity > best.Similarity) {
+			best.MemoryID, best.MemoryText, best.Similarity = m.ID, m.Text, similarity
+			found = true
+		}
+	}
+	return best, found
+}
+
+func normalizeText(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// candidates asks the tool model for memories in transcript.
+func (e *Extractor) candidates(ctx context.Context, transcript string) ([]Candidate, error) {
+	if runes := []rune(transcript); len(runes) > maxTranscriptChars {
+		transcript = string(runes[len(runes)-maxTranscriptChars:])
+	}
+
+	prompt := fmt.Sprintf(`Extract durable facts about the user from this conversation transcript, for a long-term memory.
+
+Keep only information that will still be true and useful in future conversations: preferences, tools and conventions they use, their role and projects, and standing instructions. Skip one-off requests, questions, temporary state, and anything only the assistant said unless the user confirmed it.
+
+Write each memory as one short standalone statement about the user ("Prefers tabs over spaces in Go code"). Add a few lowercase tags, and a confidence from 0 to 1 for how clearly the user stated it. Return an empty list if there is nothing worth remembering.
+
+Respond with JSON: {"memories": [{"text": "...", "tags": ["..."], "confidence": 0.9}]}
+
+Transcript:
+%s
+`, transcript)
+
+	response, err := client.CompleteJSON(ctx, e.queryClient, e.toolModel, prompt, extractSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract memories: %w", err)
+	}
+
+	var parsed struct {
+		Memories []Candidate `json:"memories"`
+	}
+	if err := json.Unmarshal([]byte(client.ExtractJSONObject(response)), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse extracted memories: %w", err)
+	}
+
+	var candidates []Candidate
+	for _, c := range parsed.Memories {
+		c.Text = strings.TrimSpace(c.Text)
+		if c.Text == "" {
+			continue
+		}
+		c.Confidence = max(0, min(c.Confidence, 1))
+		c.Tags = cleanTags(c.Tags)
+		candidates = append(candidates, c)
+	}
+	return candidates, nil
+}
+
+// cleanTags lowercases tags and drops empty and repeated ones.
+func cleanTags(tags []string) []string {
+	var out []string
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" && !slices.Contains(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// FormatTranscript renders history items, given newest first as the store
+// returns them, as a transcript in chronological order.
+func FormatTranscript(history []store.HistoryItem) string {
+	var sb strings.Builder
+	for i := len(history) - 1; i >= 0; i-- {
+		h := history[i]
+		sb.WriteString(fmt.Sprintf("%s: %s\n", h.Role, strings.TrimSpace(h.Content)))
+	}
+	return sb.String()
+}
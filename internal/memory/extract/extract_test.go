@@ -0,0 +1,126 @@
+package extract
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/memory/eval"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// scriptedStream yields a fixed response in one chunk.
+type scriptedStream struct {
+	response string
+	done     bool
+}
+
+func (s *scriptedStream) Next() bool {
+	if s.done {
+		return false
+	}
+	s.done = true
+	return true
+}
+func (s *scriptedStream) GetChunk() string { return s.response }
+func (s *scriptedStream) Err() error       { return nil }
+func (s *scriptedStream) Close() error     { return nil }
+
+// scriptedQueryClient answers every prompt with response, wrapped in chatter
+// like a model without structured output might add.
+type scriptedQueryClient struct {
+	response   string
+	lastPrompt string
+}
+
+func (c *scriptedQueryClient) ChatStream(ctx context.Context, model types.Model, query string) (client.StreamResponse, error) {
+	c.lastPrompt = query
+	return &scriptedStream{response: "Here you go:\n```json\n" + c.response + "\n```"}, nil
+}
+
+func (c *scriptedQueryClient) ChatStreamWithContext(ctx context.Context, model types.Model, systemContext, query string) (client.StreamResponse, error) {
+	return c.ChatStream(ctx, model, query)
+}
+
+func (c *scriptedQueryClient) ListModels(ctx context.Context) ([]string, error) { return nil, nil }
+
+func TestExtract(t *testing.T) {
+	s, err := store.NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	emb := eval.NewHashEmbedder()
+	existing, _ := emb.Embed(ctx, eval.HashModel, "Uses Neovim as their editor")
+	if err := s.SaveMemory(&store.MemoryItem{
+		ID: "editor", Text: "Uses Neovim as their editor", Source: store.SourceExplicit,
+		Provider: eval.HashModel.Provider, ModelID: eval.HashModel.ModelID, Dim: len(existing), Embedding: existing,
+	}); err != nil {
+		t.Fatalf("save memory: %v", err)
+	}
+
+	qc := &scriptedQueryClient{response: `{"memories": [
+		{"text": "uses neovim as their  editor", "tags": ["tools"], "confidence": 0.9},
+		{"text": "Prefers table-driven tests in Go", "tags": ["Go", "testing", "go"], "confidence": 0.85},
+		{"text": "Might be moving to Berlin", "tags": [], "confidence": 0.3},
+		{"text": "Prefers table-driven tests in Go", "tags": ["go"], "confidence": 0.8}
+	]}`}
+	cfg := utils.DefaultConfig().Memory
+	x := NewExtractor(s, emb, qc, eval.HashModel, types.Model{}, cfg)
+
+	transcript := FormatTranscript([]store.HistoryItem{
+		{Role: "assistant", Content: "Noted.", CreatedAt: time.Unix(2, 0)},
+		{Role: "user", Content: "I always write table-driven tests.", CreatedAt: time.Unix(1, 0)},
+	})
+	if want := "user: I always write table-driven tests.\nassistant: Noted.\n"; transcript != want {
+		t.Fatalf("transcript: got %q want %q", transcript, want)
+	}
+
+	result, err := x.Extract(ctx, transcript)
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if !strings.Contains(qc.lastPrompt, "I always write table-driven tests.") {
+		t.Fatalf("prompt should contain the transcript, got %q", qc.lastPrompt)
+	}
+
+	if len(result.Saved) != 1 || result.Saved[0].Text != "Prefers table-driven tests in Go" {
+		t.Fatalf("expected one saved memory, got %+v", result.Saved)
+	}
+	saved := result.Saved[0]
+	if saved.Source != store.SourceExtracted || saved.Status != store.StatusPending || saved.Confidence != 0.85 ||
+		strings.Join(saved.Tags, ",") != "go,testing" {
+		t.Fatalf("unexpected saved memory: %+v", saved)
+	}
+	if len(result.LowConfidence) != 1 || result.LowConfidence[0].Text != "Might be moving to Berlin" {
+		t.Fatalf("expected one low-confidence candidate, got %+v", result.LowConfidence)
+	}
+	// One duplicate of the stored memory and one of a candidate earlier in the transcript
+	if len(result.Duplicates) != 2 || result.Duplicates[0].MemoryID != "editor" || result.Duplicates[1].MemoryID != saved.ID {
+		t.Fatalf("expected two duplicates, got %+v", result.Duplicates)
+	}
+
+	// Pending memories are not retrievable until approved
+	if hits, _ := s.SearchMemoriesFTS("tests", 5, store.MemoryFilter{}); len(hits) != 0 {
+		t.Fatalf("pending memory should not be searchable, got %+v", hits)
+	}
+	if err := s.SetMemoryStatus(saved.ID, store.StatusActive); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if hits, _ := s.SearchMemoriesFTS("tests", 5, store.MemoryFilter{}); len(hits) != 1 {
+		t.Fatalf("approved memory should be searchable, got %+v", hits)
+	}
+
+	// Extracting the same conversation again queues nothing new
+	again, err := x.Extract(ctx, transcript)
+	if err != nil || len(again.Saved) != 0 {
+		t.Fatalf("expected no new memories on a second run, got %+v (%v)", again, err)
+	}
+}
@@ -0,0 +1,191 @@
+package ingest
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Default chunking, in characters. Chunks are small enough to embed well and to
+// fit several into an injected context, and large enough to keep a convention
+// together with its rationale.
+const (
+	DefaultChunkSize    = 1500
+	DefaultChunkOverlap = 200
+)
+
+// headingPattern matches an ATX Markdown heading: "## Title" or "## Title ##".
+var headingPattern = regexp.MustCompile(`^(#{1,6})[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+
+// section is the body of a document under one heading.
+type section struct {
+	headings []string // enclosing headings, outermost first
+	body     string
+}
+
+// ChunkMarkdown splits a Markdown document into chunks of at most size
+// characters. Chunks never span headings: each starts with the path of
+// headings it sits under ("Conventions > Errors") so it stands on its own
+// when retrieved. Long sections are split at paragraphs, then lines, then
+// words, and each piece after the first repeats the last overlap characters
+// of the one before it.
+func ChunkMarkdown(text string, size, overlap int) []string {
+	return chunkSections(markdownSections(text), size, overlap)
+}
+
+// ChunkText splits plain text into chunks of at most size characters, at
+// paragraphs where possible, overlapping like ChunkMarkdown.
+func ChunkText(text string, size, overlap int) []string {
+	return chunkSections([]section{{body: text}}, size, overlap)
+}
+
+// markdownSections splits text at ATX headings outside fenced code blocks.
+// Sections without any text of their own are dropped; their heading still
+// appears in the path of the sections below it.
+func markdownSections(text string) []section {
+	var (
+		sections []section
+		headings []string
+		levels   []int
+		body     strings.Builder
+		fence    string
+	)
+
+	flush := func() {
+		if strings.TrimSpace(body.String()) != "" {
+			sections = append(sections, section{headings: append([]string(nil), headings...), body: body.String()})
+		}
+		body.Reset()
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```"), strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		default:
+			m := headingPattern.FindStringSubmatch(line)
+			if m == nil {
+				break
+			}
+			flush()
+			level := len(m[1])
+			for len(levels) > 0 && levels[len(levels)-1] >= level {
+				levels, headings = levels[:len(levels)-1], headings[:len(headings)-1]
+			}
+			levels, headings = append(levels, level), append(headings, m[2])
+			continue
+		}
+		body.WriteString(line)
+		body.WriteByte('\n')
+	}
+	flush()
+
+	return sections
+}
+
+// chunkSections splits each section into chunks prefixed with its heading path.
+func chunkSections(sections []section, size, overlap int) []string {
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+	overlap = min(max(overlap, 0), size/4)
+
+	var chunks []string
+	for _, sec := range sections {
+		body := strings.TrimSpace(sec.body)
+		if body == "" {
+			continue
+		}
+
+		prefix := ""
+		if len(sec.headings) > 0 {
+			prefix = strings.Join(sec.headings, " > ") + "\n\n"
+		}
+		// Leave room for the heading path and the overlap, but never less
+		// than half a chunk for the text itself
+		budget := max(size-utf8.RuneCountInString(prefix)-overlap, size/2)
+
+		pieces := splitText(body, budget)
+		for i, piece := range pieces {
+			if i > 0 && overlap > 0 {
+				piece = tail(pieces[i-1], overlap) + " " + piece
+			}
+			chunks = append(chunks, prefix+piece)
+		}
+	}
+	return chunks
+}
+
+// separators are tried in order when text has to be split.
+var separators = []string{"\n\n", "\n", " "}
+
+// splitText splits text into pieces of at most budget characters, cutting at
+// the coarsest separator that works.
+func splitText(text string, budget int) []string {
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) <= budget {
+		return []string{text}
+	}
+
+	for _, sep := range separators {
+		parts := strings.Split(text, sep)
+		if len(parts) < 2 {
+			continue
+		}
+
+		var pieces []string
+		var current strings.Builder
+		emit := func() {
+			if s := strings.TrimSpace(current.String()); s != "" {
+				pieces = append(pieces, s)
+			}
+			current.Reset()
+		}
+		for _, part := range parts {
+			if strings.TrimSpace(part) == "" {
+				continue
+			}
+			if utf8.RuneCountInString(part) > budget {
+				emit()
+				pieces = append(pieces, splitText(part, budget)...)
+				continue
+			}
+			if current.Len() > 0 && utf8.RuneCountInString(current.String())+len(sep)+utf8.RuneCountInString(part) > budget {
+				emit()
+			}
+			if current.Len() > 0 {
+				current.WriteString(sep)
+			}
+			current.WriteString(part)
+		}
+		emit()
+		return pieces
+	}
+
+	// A single word longer than the budget
+	runes := []rune(text)
+	var pieces []string
+	for len(runes) > budget {
+		pieces = append(pieces, string(runes[:budget]))
+		runes = runes[budget:]
+	}
+	return append(pieces, string(runes))
+}
+
+// tail returns about the last n characters of text, starting at a word boundary.
+func tail(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return strings.Join(strings.Fields(text), " ")
+	}
+	cut := string(runes[len(runes)-n:])
+	if i := strings.IndexAny(cut, " \n\t"); i >= 0 && i < len(cut)-1 {
+		cut = cut[i+1:]
+	}
+	return strings.Join(strings.Fields(cut), " ")
+}
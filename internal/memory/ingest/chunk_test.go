@@ -0,0 +1,55 @@
+package ingest
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestChunkMarkdown_Headings(t *testing.T) {
+	doc := "Intro paragraph.\n\n# Conventions\n\n## Errors\n\nWrap errors with %w.\n\n```go\n# not a heading\n```\n\n## Naming ##\n\nUse short names.\n\n# Tooling\n\nRun gofmt.\n"
+
+	got := ChunkMarkdown(doc, 500, 50)
+	want := []string{
+		"Intro paragraph.",
+		"Conventions > Errors\n\nWrap errors with %w.\n\n```go\n# not a heading\n```",
+		"Conventions > Naming\n\nUse short names.",
+		"Tooling\n\nRun gofmt.",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("got %q\nwant %q", got, want)
+	}
+}
+
+func TestChunkMarkdown_LongSectionOverlaps(t *testing.T) {
+	var paragraphs []string
+	for i := 0; i < 12; i++ {
+		paragraphs = append(paragraphs, strings.Repeat("word ", 30)+"end.")
+	}
+	doc := "# Guide\n\n" + strings.Join(paragraphs, "\n\n")
+
+	chunks := ChunkMarkdown(doc, 400, 60)
+	if len(chunks) < 3 {
+		t.Fatalf("expected the section to be split, got %d chunks", len(chunks))
+	}
+	for i, c := range chunks {
+		if n := utf8.RuneCountInString(c); n > 400 {
+			t.Errorf("chunk %d has %d characters, over the limit", i, n)
+		}
+		if !strings.HasPrefix(c, "Guide\n\n") {
+			t.Errorf("chunk %d lost its heading: %q", i, c)
+		}
+	}
+	// Each later chunk starts with the end of the one before it
+	prevTail := tail(strings.TrimPrefix(chunks[0], "Guide\n\n"), 60)
+	if !strings.HasPrefix(strings.TrimPrefix(chunks[1], "Guide\n\n"), prevTail) {
+		t.Fatalf("expected chunk 1 to start with %q, got %q", prevTail, chunks[1])
+	}
+}
+
+func TestChunkText_SplitsLongWords(t *testing.T) {
+	chunks := ChunkText(strings.Repeat("x", 250), 100, -1)
+	if len(chunks) != 3 || chunks[2] != strings.Repeat("x", 50) {
+		t.Fatalf("expected a hard split into 3 chunks, got %q", chunks)
+	}
+}
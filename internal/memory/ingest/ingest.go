@@ -0,0 +1,276 @@
+// Package ingest loads Markdown and plain-text documents into the memory
+// store as chunks, so that long documents such as team conventions or ADRs
+// can be retrieved piece by piece.
+package ingest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/memory/memutils"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/types"
+)
+
+// embedBatchSize is how many chunks are sent per EmbedBatch call.
+const embedBatchSize = 64
+
+// File extensions ingest reads, by format.
+var (
+	markdownExts = map[string]bool{".md": true, ".markdown": true, ".mdx": true}
+	textExts     = map[string]bool{".txt": true, ".text": true}
+)
+
+// Options configures an ingest run.
+type Options struct {
+	EmbeddingClient client.EmbeddingClient
+	EmbeddingModel  types.Model
+	// ChunkSize and ChunkOverlap are in characters; 0 uses the defaults and
+	// a negative overlap turns overlapping off.
+	ChunkSize    int
+	ChunkOverlap int
+	// Tags are added to every chunk.
+	Tags []string
+}
+
+// FileResult describes what ingesting one file changed.
+type FileResult struct {
+	Path       string
+	DocumentID string
+	Chunks     int  // chunks the file now has
+	Embedded   int  // new or changed chunks that were embedded
+	Kept       int  // unchanged chunks that kept their embedding
+	Removed    int  // stored chunks that no longer exist
+	Written    bool // whether the stored chunks were rewritten
+	Err        error
+}
+
+// Unchanged reports whether the stored document already matched the file,
+// so nothing was written.
+func (r FileResult) Unchanged() bool {
+	return r.Err == nil && !r.Written
+}
+
+// CollectFiles expands paths into the Markdown and text files to ingest.
+// Directories are walked recursively, skipping hidden entries and files of
+// other types; files named explicitly must be Markdown or text.
+func CollectFiles(paths []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			if !supported(p) {
+				return nil, fmt.Errorf("%s: unsupported file type (expected Markdown or plain text)", p)
+			}
+			add(p)
+			continue
+		}
+
+		var found []string
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if path != p && strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.IsDir() && supported(path) {
+				found = append(found, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(found)
+		for _, f := range found {
+			add(f)
+		}
+	}
+	return files, nil
+}
+
+func supported(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return markdownExts[ext] || textExts[ext]
+}
+
+// Run ingests each file. A file that fails is reported in its result and
+// doesn't stop the others.
+func Run(ctx context.Context, s *store.Store, files []string, opts Options) []FileResult {
+	results := make([]FileResult, 0, len(files))
+	for _, path := range files {
+		results = append(results, File(ctx, s, path, opts))
+	}
+	return results
+}
+
+// File ingests one document. The document ID is derived from the file's
+// absolute path, so ingesting the same file again updates its chunks in
+// place: chunks whose text is unchanged keep their embedding, and only new
+// or changed chunks are embedded.
+func File(ctx context.Context, s *store.Store, path string, opts Options) FileResult {
+	result := FileResult{Path: path}
+	fail := func(err error) FileResult {
+		result.Err = err
+		return result
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fail(err)
+	}
+	result.DocumentID = DocumentID(abs)
+
+	content, err := os.ReadFile(abs)
+	if err != nil {
+		return fail(err)
+	}
+
+	size, overlap := opts.ChunkSize, opts.ChunkOverlap
+	if size == 0 {
+		size = DefaultChunkSize
+	}
+	if overlap == 0 {
+		overlap = DefaultChunkOverlap
+	}
+	var texts []string
+	if markdownExts[strings.ToLower(filepath.Ext(abs))] {
+		texts = ChunkMarkdown(string(content), size, overlap)
+	} else {
+		texts = ChunkText(string(content), size, overlap)
+	}
+	result.Chunks = len(texts)
+
+	existing, err := s.GetDocumentChunks(result.DocumentID)
+	if err != nil {
+		return fail(err)
+	}
+	// Stored chunks that can be reused, by content hash. Chunks embedded with
+	// another model are re-embedded so the document stays searchable.
+	reusable := make(map[string][]store.MemoryItem)
+	for _, c := range existing {
+		if c.Provider == opts.EmbeddingModel.Provider && c.ModelID == opts.EmbeddingModel.ModelID {
+			reusable[c.ContentHash] = append(reusable[c.ContentHash], c)
+		}
+	}
+
+	chunks := make([]store.MemoryItem, len(texts))
+	var pending []int
+	for i, text := range texts {
+		hash := contentHash(text)
+		chunk := store.MemoryItem{
+			Text:        text,
+			Tags:        opts.Tags,
+			Source:      store.SourceDocument,
+			ChunkIndex:  i,
+			SourcePath:  abs,
+			ContentHash: hash,
+		}
+		if matches := reusable[hash]; len(matches) > 0 {
+			reusable[hash] = matches[1:]
+			chunk.ID, chunk.CreatedAt = matches[0].ID, matches[0].CreatedAt
+			result.Kept++
+		} else {
+			pending = append(pending, i)
+		}
+		chunks[i] = chunk
+	}
+	result.Removed = len(existing) - result.Kept
+
+	if len(pending) == 0 && sameOrder(existing, chunks) {
+		return result
+	}
+
+	if err := embedChunks(ctx, opts, chunks, pending); err != nil {
+		return fail(err)
+	}
+	if err := s.ReplaceDocumentChunks(result.DocumentID, chunks); err != nil {
+		return fail(err)
+	}
+	result.Embedded = len(pending)
+	result.Written = true
+	return result
+}
+
+// embedChunks embeds the chunks at the pending indexes in batches.
+func embedChunks(ctx context.Context, opts Options, chunks []store.MemoryItem, pending []int) error {
+	if len(pending) > 0 && opts.EmbeddingClient == nil {
+		return fmt.Errorf("no embedding client configured")
+	}
+
+	for start := 0; start < len(pending); start += embedBatchSize {
+		batch := pending[start:min(start+embedBatchSize, len(pending))]
+		texts := make([]string, len(batch))
+		for i, idx := range batch {
+			texts[i] = chunks[idx].Text
+		}
+
+		embeddings, err := opts.EmbeddingClient.EmbedBatch(ctx, opts.EmbeddingModel, texts)
+		if err != nil {
+			return fmt.Errorf("failed to embed chunks: %w", err)
+		}
+		if len(embeddings) != len(batch) {
+			return fmt.Errorf("failed to embed chunks: got %d embeddings for %d chunks", len(embeddings), len(batch))
+		}
+
+		for i, idx := range batch {
+			normalized := memutils.NormalizeVector(embeddings[i])
+			chunks[idx].Provider = opts.EmbeddingModel.Provider
+			chunks[idx].ModelID = opts.EmbeddingModel.ModelID
+			chunks[idx].Dim = len(normalized)
+			chunks[idx].Embedding = normalized
+		}
+	}
+	return nil
+}
+
+// sameOrder reports whether the stored chunks already have the new chunks'
+// IDs, order, source path and tags, so there is nothing to write.
+func sameOrder(existing, chunks []store.MemoryItem) bool {
+	if len(existing) != len(chunks) {
+		return false
+	}
+	for i := range chunks {
+		if existing[i].ID != chunks[i].ID || existing[i].ChunkIndex != chunks[i].ChunkIndex ||
+			existing[i].SourcePath != chunks[i].SourcePath ||
+			strings.Join(existing[i].Tags, "\x00") != strings.Join(chunks[i].Tags, "\x00") {
+			return false
+		}
+	}
+	return true
+}
+
+// DocumentID returns the stable document ID for the file at an absolute path.
+func DocumentID(absPath string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte("file://"+filepath.ToSlash(absPath))).String()
+}
+
+func contentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
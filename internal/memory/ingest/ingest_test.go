@@ -0,0 +1,107 @@
+package ingest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/austiecodes/gomor/internal/memory/eval"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/types"
+)
+
+// countingEmbedder records which texts were embedded.
+type countingEmbedder struct {
+	eval.HashEmbedder
+	embedded []string
+}
+
+func (c *countingEmbedder) EmbedBatch(ctx context.Context, model types.Model, texts []string) ([][]float32, error) {
+	c.embedded = append(c.embedded, texts...)
+	return c.HashEmbedder.EmbedBatch(ctx, model, texts)
+}
+
+func TestFile_ReingestReplacesOnlyChangedChunks(t *testing.T) {
+	dir := t.TempDir()
+	s, err := store.NewStoreWithPath(filepath.Join(dir, "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	docs := filepath.Join(dir, "docs")
+	if err := os.MkdirAll(filepath.Join(docs, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(docs, "conventions.md")
+	write := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("# Errors\n\nWrap errors with %w.\n\n# Naming\n\nUse short names.\n\n# Tests\n\nTable-driven tests.\n")
+	os.WriteFile(filepath.Join(docs, ".git", "notes.md"), []byte("# Hidden"), 0o644)
+	os.WriteFile(filepath.Join(docs, "logo.png"), []byte{0x89}, 0o644)
+
+	files, err := CollectFiles([]string{docs})
+	if err != nil || len(files) != 1 || files[0] != path {
+		t.Fatalf("expected only conventions.md, got %q (%v)", files, err)
+	}
+
+	emb := &countingEmbedder{HashEmbedder: *eval.NewHashEmbedder()}
+	opts := Options{EmbeddingClient: emb, EmbeddingModel: eval.HashModel, Tags: []string{"conventions"}}
+
+	first := File(context.Background(), s, path, opts)
+	if first.Err != nil || first.Chunks != 3 || first.Embedded != 3 {
+		t.Fatalf("first ingest: %+v", first)
+	}
+	before, _ := s.GetDocumentChunks(first.DocumentID)
+
+	// Unchanged file: nothing is embedded or written
+	emb.embedded = nil
+	if again := File(context.Background(), s, path, opts); !again.Unchanged() || len(emb.embedded) != 0 {
+		t.Fatalf("expected an unchanged re-ingest, got %+v embedding %q", again, emb.embedded)
+	}
+
+	// New tags rewrite the chunks without embedding them again
+	tagged := opts
+	tagged.Tags = []string{"conventions", "go"}
+	if retagged := File(context.Background(), s, path, tagged); retagged.Unchanged() || !retagged.Written || retagged.Embedded != 0 || len(emb.embedded) != 0 {
+		t.Fatalf("expected a rewrite without embedding, got %+v embedding %q", retagged, emb.embedded)
+	}
+	if chunks, _ := s.GetDocumentChunks(first.DocumentID); len(chunks) != 3 || len(chunks[0].Tags) != 2 {
+		t.Fatalf("expected the new tags on every chunk, got %+v", chunks)
+	}
+
+	// Change one section and drop another
+	write("# Errors\n\nWrap errors with %w and add context.\n\n# Tests\n\nTable-driven tests.\n")
+	emb.embedded = nil
+	second := File(context.Background(), s, path, opts)
+	if second.Err != nil || second.Chunks != 2 || second.Embedded != 1 || second.Kept != 1 || second.Removed != 2 {
+		t.Fatalf("second ingest: %+v", second)
+	}
+	if len(emb.embedded) != 1 || !strings.Contains(emb.embedded[0], "add context") {
+		t.Fatalf("expected only the changed chunk to be embedded, got %q", emb.embedded)
+	}
+
+	after, err := s.GetDocumentChunks(first.DocumentID)
+	if err != nil || len(after) != 2 {
+		t.Fatalf("expected 2 stored chunks, got %d (%v)", len(after), err)
+	}
+	if after[1].ID != before[2].ID || after[1].ChunkIndex != 1 {
+		t.Fatalf("expected the unchanged Tests chunk to keep its ID and move to index 1, got %+v", after[1])
+	}
+	for _, c := range after {
+		if c.Source != store.SourceDocument || c.SourcePath != path || len(c.Tags) != 1 {
+			t.Fatalf("unexpected chunk metadata: %+v", c)
+		}
+	}
+
+	// Chunks are found by full-text search like any memory
+	hits, err := s.SearchMemoriesFTS("context", 5, store.MemoryFilter{Source: store.SourceDocument})
+	if err != nil || len(hits) != 1 || hits[0].Item.DocumentID != first.DocumentID {
+		t.Fatalf("expected the changed chunk from FTS, got %+v (%v)", hits, err)
+	}
+}
@@ -1,6 +1,9 @@
 package memtypes
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // MemorySource indicates how a memory was created.
 type MemorySource string
@@ -10,6 +13,20 @@ const (
 	SourceExplicit MemorySource = "explicit"
 	// SourceExtracted means the memory was automatically extracted from conversation.
 	SourceExtracted MemorySource = "extracted"
+	// SourceDocument means the memory is a chunk of an ingested document.
+	SourceDocument MemorySource = "document"
+)
+
+// MemoryStatus says whether retrieval may return a memory.
+type MemoryStatus string
+
+const (
+	// StatusActive memories are returned by retrieval.
+	StatusActive MemoryStatus = "active"
+	// StatusPending memories were extracted automatically and wait for review.
+	StatusPending MemoryStatus = "pending"
+	// StatusArchived memories were merged into another by consolidation.
+	StatusArchived MemoryStatus = "archived"
 )
 
 // MemoryItem represents a single preference/fact stored in memory.
@@ -23,6 +40,75 @@ type MemoryItem struct {
 	ModelID   string       `json:"model_id"`
 	Dim       int          `json:"dim"`
 	Embedding []float32    `json:"-"` // stored as blob, not JSON
+
+	// Status is StatusActive when empty.
+	Status MemoryStatus `json:"status,omitempty"`
+	// Confidence is how sure the extractor was, from 0 to 1; 0 for memories
+	// that weren't extracted.
+	Confidence float64 `json:"confidence,omitempty"`
+
+	// Pinned memories always go into the profile document.
+	Pinned bool `json:"pinned,omitempty"`
+	// Importance is from 0 to 1; 0 when unset. Important memories go into
+	// the profile document along with pinned ones.
+	Importance float64 `json:"importance,omitempty"`
+
+	// Set for chunks of an ingested document, empty for standalone memories.
+	DocumentID  string `json:"document_id,omitempty"`
+	ChunkIndex  int    `json:"chunk_index,omitempty"`
+	SourcePath  string `json:"source_path,omitempty"`
+	ContentHash string `json:"content_hash,omitempty"`
+}
+
+// Profile is one version of the profile document: a short summary of who
+// the user is, written from their pinned and most important memories.
+type Profile struct {
+	Version      int               `json:"version"`
+	Content      string            `json:"content"`
+	MemoryIDs    []string          `json:"memory_ids"`    // memories the profile was written from
+	MemoryHashes map[string]string `json:"memory_hashes"` // hash of what each of them said, by ID
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// MemoryFilter restricts which memories a search may return.
+// Zero-valued fields don't filter.
+type MemoryFilter struct {
+	Tags          []string     `json:"tags,omitempty"`           // memory must have all of these tags (case-insensitive)
+	Source        MemorySource `json:"source,omitempty"`         // memory must come from this source
+	CreatedAfter  time.Time    `json:"created_after,omitempty"`  // created at or after this time
+	CreatedBefore time.Time    `json:"created_before,omitempty"` // created strictly before this time
+}
+
+// IsZero reports whether the filter matches every memory.
+func (f MemoryFilter) IsZero() bool {
+	return len(f.Tags) == 0 && f.Source == "" && f.CreatedAfter.IsZero() && f.CreatedBefore.IsZero()
+}
+
+// Match reports whether item passes the filter. It mirrors the SQL filter
+// used by the store, for filtering memories that are already loaded.
+func (f MemoryFilter) Match(item MemoryItem) bool {
+	if f.Source != "" && item.Source != f.Source {
+		return false
+	}
+	if !f.CreatedAfter.IsZero() && item.CreatedAt.Before(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !item.CreatedAt.Before(f.CreatedBefore) {
+		return false
+	}
+	for _, want := range f.Tags {
+		found := false
+		for _, have := range item.Tags {
+			if strings.EqualFold(have, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
 }
 
 // HistoryItem represents a conversation turn stored in history.
@@ -71,8 +157,91 @@ type InjectedContext struct {
 	HistorySnippets []HistorySearchResult `json:"history_snippets,omitempty"`
 }
 
+// ModelMismatch counts memories that vector search skipped because they were
+// embedded with a different model, or dimension, than the query.
+type ModelMismatch struct {
+	Provider string `json:"provider"`
+	ModelID  string `json:"model_id"`
+	Dim      int    `json:"dim"`
+	Count    int    `json:"count"`
+}
+
 // RetrievalResponse represents the response from the unified memory retrieve operation.
 type RetrievalResponse struct {
-	Results []UnifiedResult `json:"results"`
-	Query   string          `json:"query"`
+	Results         []UnifiedResult       `json:"results"`
+	HistorySnippets []HistorySearchResult `json:"history_snippets,omitempty"`
+	Query           string                `json:"query"`
+	// Mismatched lists memories left out of vector search; they can still be found by FTS.
+	Mismatched []ModelMismatch `json:"mismatched,omitempty"`
+	// Degraded is set when a stage failed or ran out of time, so the results
+	// may be incomplete; DegradedReasons says which stages and why.
+	Degraded        bool     `json:"degraded,omitempty"`
+	DegradedReasons []string `json:"degraded_reasons,omitempty"`
+	// Explain is set only when explain mode was requested.
+	Explain *RetrievalTrace `json:"explain,omitempty"`
+}
+
+// RetrievalTrace records how a retrieval arrived at its results.
+type RetrievalTrace struct {
+	TransformedQueries []string      `json:"transformed_queries"`
+	VectorSearches     []VectorTrace `json:"vector_searches"`
+	FTSSearches        []FTSTrace    `json:"fts_searches"`
+	FusionStrategy     string        `json:"fusion_strategy"`
+	Results            []ResultTrace `json:"results"`
+	Timings            []StageTiming `json:"timings"`
+}
+
+// VectorTrace is one embedded query and the memories it matched.
+type VectorTrace struct {
+	Query string     `json:"query"`
+	Model string     `json:"model"`
+	Hits  []TraceHit `json:"hits"`
+	Error string     `json:"error,omitempty"`
+}
+
+// FTSTrace is one full-text query: the text it was built from (the raw query,
+// or the tool model's summary of it) and the FTS5 expression that was run.
+type FTSTrace struct {
+	Strategy   string     `json:"strategy"` // "direct" or "summary"
+	Input      string     `json:"input"`
+	Expression string     `json:"expression"`
+	Hits       []TraceHit `json:"hits"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// TraceHit is a memory returned by a single search. Score is the cosine
+// similarity for vector hits and the raw bm25 rank (lower is better) for FTS.
+type TraceHit struct {
+	ID    string  `json:"id"`
+	Text  string  `json:"text"`
+	Score float64 `json:"score"`
+}
+
+// ScoreTerm is one contribution to a fused score.
+type ScoreTerm struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// ResultTrace explains the final score of one returned memory. The fusion
+// terms add up to FusedScore, which is multiplied by RecencyFactor to give
+// Score; a rerank grade, if any, reorders results without changing Score.
+type ResultTrace struct {
+	ID            string      `json:"id"`
+	Text          string      `json:"text"`
+	Source        string      `json:"source"`
+	VectorPos     int         `json:"vector_pos,omitempty"` // 1-based, 0 if absent
+	FTSPos        int         `json:"fts_pos,omitempty"`
+	Terms         []ScoreTerm `json:"terms"`
+	FusedScore    float64     `json:"fused_score"`
+	RecencyFactor float64     `json:"recency_factor"`
+	Score         float64     `json:"score"`
+	RerankGrade   *int        `json:"rerank_grade,omitempty"`
+}
+
+// StageTiming is the wall time spent in one retrieval stage. Vector, FTS and
+// history search run concurrently, so their timings overlap.
+type StageTiming struct {
+	Stage      string  `json:"stage"`
+	DurationMs float64 `json:"duration_ms"`
 }
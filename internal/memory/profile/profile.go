@@ -0,0 +1,207 @@
+// Package profile maintains the profile document: a short Markdown summary
+// of who the user is, written by the tool model from their pinned and most
+// important memories, so agents can read it at the start of a session
+// instead of issuing many retrievals.
+package profile
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// URI is the MCP resource URI of the profile document.
+const URI = "gomor://profile"
+
+// EmptyContent is the profile written when no memory is pinned or important enough.
+const EmptyContent = "# User profile\n\nNothing is known about the user yet. Pin memories, or save them with a high importance, to build this profile.\n"
+
+// Generator writes new versions of the profile document.
+type Generator struct {
+	store       *store.Store
+	queryClient client.QueryClient
+	toolModel   types.Model
+	config      utils.MemoryConfig
+}
+
+// NewGenerator creates a new profile generator. queryClient may be nil, in
+// which case the profile can be read but not regenerated.
+func NewGenerator(s *store.Store, queryClient client.QueryClient, toolModel types.Model, config utils.MemoryConfig) *Generator {
+	return &Generator{
+		store:       s,
+		queryClient: queryClient,
+		toolModel:   toolModel,
+		config:      config,
+	}
+}
+
+// Sources returns the memories the profile is written from: active memories
+// that are pinned or at least as important as the configured minimum,
+// oldest first.
+func (g *Generator) Sources() ([]store.MemoryItem, error) {
+	memories, err := g.store.GetMemories(store.MemoryFilter{})
+	if err != nil {
+		return nil, err
+	}
+	var sources []store.MemoryItem
+	for _, m := range memories {
+		if Qualifies(m, g.config) {
+			sources = append(sources, m)
+		}
+	}
+	sort.SliceStable(sources, func(i, j int) bool { return sources[i].CreatedAt.Before(sources[j].CreatedAt) })
+	return sources, nil
+}
+
+// Qualifies reports whether an active memory belongs in the profile.
+func Qualifies(m store.MemoryItem, config utils.MemoryConfig) bool {
+	if m.Status != "" && m.Status != store.StatusActive {
+		return false
+	}
+	return m.Pinned || (m.Importance > 0 && m.Importance >= config.ProfileMinImportance)
+}
+
+// Stale reports whether current, the latest stored profile or nil, was
+// written from a different set of memories than sources, or from an older
+// version of one of them.
+func Stale(current *store.Profile, sources []store.MemoryItem) bool {
+	if current == nil {
+		return true
+	}
+	ids := make([]string, len(sources))
+	for i, m := range sources {
+		ids[i] = m.ID
+		if current.MemoryHashes[m.ID] != Hash(m) {
+			return true
+		}
+	}
+	have := slices.Clone(current.MemoryIDs)
+	slices.Sort(ids)
+	slices.Sort(have)
+	return !slices.Equal(ids, have)
+}
+
+// Hash returns a hash of what a memory says in the profile: its text, tags
+// and whether it is pinned.
+func Hash(m store.MemoryItem) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%q %q %t", m.Text, m.Tags, m.Pinned)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Refresh returns the profile, writing a new version first if the memories
+// it is written from changed since the latest one. The new version updates
+// the previous one rather than starting over, so its wording stays stable.
+// updated reports whether a new version was written. When another refresh
+// saves a version first, that one is returned instead.
+func (g *Generator) Refresh(ctx context.Context) (profile *store.Profile, updated bool, err error) {
+	current, err := g.store.GetProfile()
+	if err != nil {
+		return nil, false, err
+	}
+	sources, err := g.Sources()
+	if err != nil {
+		return nil, false, err
+	}
+	if !Stale(current, sources) {
+		return current, false, nil
+	}
+
+	ids := make([]string, len(sources))
+	hashes := make(map[string]string, len(sources))
+	for i, m := range sources {
+		ids[i] = m.ID
+		hashes[m.ID] = Hash(m)
+	}
+
+	content := EmptyContent
+	if len(sources) > 0 {
+		if g.queryClient == nil {
+			return nil, false, fmt.Errorf("tool model not configured. Run 'gomor set' to configure")
+		}
+		if content, err = g.write(ctx, current, sources); err != nil {
+			return nil, false, err
+		}
+	}
+
+	var revises int
+	if current != nil {
+		revises = current.Version
+	}
+	profile, err = g.store.SaveProfile(content, ids, hashes, revises)
+	if errors.Is(err, store.ErrProfileChanged) {
+		profile, err = g.store.GetProfile()
+		return profile, false, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return profile, true, nil
+}
+
+// write asks the tool model for the profile document. With a current
+// profile, the model revises it for the memories added, edited and removed
+// since.
+func (g *Generator) write(ctx context.Context, current *store.Profile, sources []store.MemoryItem) (string, error) {
+	var previous map[string]bool
+	if current != nil {
+		previous = make(map[string]bool, len(current.MemoryIDs))
+		for _, id := range current.MemoryIDs {
+			previous[id] = true
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`Write a short profile of the user for an AI assistant to read at the start of every conversation. Use Markdown: a "# User profile" heading, then a few sections with bullet points, such as role and projects, preferences and conventions, and standing instructions. Keep it under 300 words, only state what the memories say, and where memories conflict, prefer the most recent. Respond with the profile only.
+`)
+	if current != nil && len(current.MemoryIDs) > 0 {
+		sb.WriteString(`
+Revise the current profile below instead of starting over: keep its wording where it is still right, add what the new memories say, update what edited memories now say, and drop anything only supported by memories that were removed.
+
+Current profile:
+`)
+		sb.WriteString(current.Content)
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\nMemories (oldest first; pinned ones matter most):\n")
+	for _, m := range sources {
+		sb.WriteString("- ")
+		if previous != nil {
+			if hash, ok := current.MemoryHashes[m.ID]; !previous[m.ID] {
+				sb.WriteString("(new) ")
+			} else if ok && hash != Hash(m) {
+				sb.WriteString("(edited) ")
+			}
+		}
+		if m.Pinned {
+			sb.WriteString("(pinned) ")
+		}
+		sb.WriteString(fmt.Sprintf("[%s] %s\n", m.CreatedAt.Format(time.DateOnly), m.Text))
+	}
+
+	stream, err := g.queryClient.ChatStream(ctx, g.toolModel, sb.String())
+	if err != nil {
+		return "", fmt.Errorf("failed to write profile: %w", err)
+	}
+	content, err := client.ReadStream(stream)
+	if err != nil {
+		return "", fmt.Errorf("failed to write profile: %w", err)
+	}
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", fmt.Errorf("tool model returned an empty profile")
+	}
+	return content + "\n", nil
+}
@@ -0,0 +1,169 @@
+package profile
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// fakeStream streams a single canned response.
+type fakeStream struct {
+	text string
+	done bool
+}
+
+func (s *fakeStream) Next() bool {
+	if s.done {
+		return false
+	}
+	s.done = true
+	return true
+}
+
+func (s *fakeStream) GetChunk() string { return s.text }
+func (s *fakeStream) Err() error       { return nil }
+func (s *fakeStream) Close() error     { return nil }
+
+// profileClient answers every prompt with a numbered profile and records the prompts.
+type profileClient struct {
+	prompts []string
+}
+
+func (c *profileClient) ChatStream(ctx context.Context, model types.Model, query string) (client.StreamResponse, error) {
+	c.prompts = append(c.prompts, query)
+	return &fakeStream{text: "# User profile\n\nrevision " + string(rune('0'+len(c.prompts)))}, nil
+}
+
+func (c *profileClient) ChatStreamWithContext(ctx context.Context, model types.Model, systemContext, query string) (client.StreamResponse, error) {
+	return c.ChatStream(ctx, model, query)
+}
+
+func (c *profileClient) ListModels(ctx context.Context) ([]string, error) { return nil, nil }
+
+func newTestStore(t *testing.T) *store.Store {
+	t.Helper()
+	s, err := store.NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func save(t *testing.T, s *store.Store, item store.MemoryItem) store.MemoryItem {
+	t.Helper()
+	item.Source = store.SourceExplicit
+	if err := s.SaveMemory(&item); err != nil {
+		t.Fatalf("save memory: %v", err)
+	}
+	return item
+}
+
+func TestRefresh(t *testing.T) {
+	s := newTestStore(t)
+	qc := &profileClient{}
+	g := NewGenerator(s, qc, types.Model{}, utils.MemoryConfig{ProfileMinImportance: 0.7})
+	ctx := context.Background()
+
+	// Nothing qualifies yet: the placeholder is written without the tool model
+	p, updated, err := g.Refresh(ctx)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if !updated || p.Version != 1 || p.Content != EmptyContent || len(qc.prompts) != 0 {
+		t.Fatalf("expected placeholder version 1, got %+v (updated %v, %d prompts)", p, updated, len(qc.prompts))
+	}
+
+	pinned := save(t, s, store.MemoryItem{Text: "Works on the gomor memory server", Pinned: true, CreatedAt: time.Unix(100, 0)})
+	save(t, s, store.MemoryItem{Text: "Asked about the weather once", Importance: 0.2})
+	pending := save(t, s, store.MemoryItem{Text: "Maybe likes Rust", Importance: 0.9, Status: store.StatusPending})
+
+	p, updated, err = g.Refresh(ctx)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if !updated || p.Version != 2 || len(p.MemoryIDs) != 1 || p.MemoryIDs[0] != pinned.ID {
+		t.Fatalf("expected version 2 from the pinned memory, got %+v", p)
+	}
+	if strings.Contains(qc.prompts[0], "weather") || strings.Contains(qc.prompts[0], "Rust") {
+		t.Fatalf("unimportant and pending memories should be left out:\n%s", qc.prompts[0])
+	}
+
+	// Unchanged sources keep the version
+	p, updated, err = g.Refresh(ctx)
+	if err != nil || updated || p.Version != 2 || len(qc.prompts) != 1 {
+		t.Fatalf("expected version 2 to be kept, got %+v (updated %v, err %v)", p, updated, err)
+	}
+
+	// Approving the pending memory revises the current profile
+	if err := s.SetMemoryStatus(pending.ID, store.StatusActive); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	p, updated, err = g.Refresh(ctx)
+	if err != nil || !updated || p.Version != 3 || len(p.MemoryIDs) != 2 {
+		t.Fatalf("expected version 3 from two memories, got %+v (updated %v, err %v)", p, updated, err)
+	}
+	prompt := qc.prompts[1]
+	if !strings.Contains(prompt, "Current profile:\n# User profile\n\nrevision 1") || !strings.Contains(prompt, "(new) [") {
+		t.Fatalf("expected an incremental prompt, got:\n%s", prompt)
+	}
+
+	latest, err := s.GetProfile()
+	if err != nil || latest.Version != 3 || latest.Content != p.Content {
+		t.Fatalf("expected version 3 to be stored, got %+v (err %v)", latest, err)
+	}
+}
+
+func TestRefreshEditedMemory(t *testing.T) {
+	s := newTestStore(t)
+	qc := &profileClient{}
+	g := NewGenerator(s, qc, types.Model{}, utils.MemoryConfig{ProfileMinImportance: 0.7})
+	ctx := context.Background()
+
+	pinned := save(t, s, store.MemoryItem{Text: "Works at Acme", Pinned: true})
+	if _, _, err := g.Refresh(ctx); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	// Editing the text of a pinned memory keeps its ID but revises the profile
+	pinned.Text = "Works at Globex"
+	if err := s.UpdateMemory(&pinned); err != nil {
+		t.Fatalf("update memory: %v", err)
+	}
+	p, updated, err := g.Refresh(ctx)
+	if err != nil || !updated || p.Version != 2 {
+		t.Fatalf("expected version 2 after the edit, got %+v (updated %v, err %v)", p, updated, err)
+	}
+	if prompt := qc.prompts[1]; !strings.Contains(prompt, "(edited) (pinned) [") || !strings.Contains(prompt, "Globex") {
+		t.Fatalf("expected the edited memory to be marked, got:\n%s", prompt)
+	}
+
+	// So does editing its tags
+	pinned.Tags = []string{"work"}
+	if err := s.UpdateMemory(&pinned); err != nil {
+		t.Fatalf("update memory: %v", err)
+	}
+	if p, updated, err = g.Refresh(ctx); err != nil || !updated || p.Version != 3 {
+		t.Fatalf("expected version 3 after the tag edit, got %+v (updated %v, err %v)", p, updated, err)
+	}
+	if p, updated, err = g.Refresh(ctx); err != nil || updated || p.Version != 3 {
+		t.Fatalf("expected version 3 to be kept, got %+v (updated %v, err %v)", p, updated, err)
+	}
+}
+
+func TestRefreshWithoutToolModel(t *testing.T) {
+	s := newTestStore(t)
+	save(t, s, store.MemoryItem{Text: "Prefers tabs", Pinned: true})
+
+	g := NewGenerator(s, nil, types.Model{}, utils.MemoryConfig{ProfileMinImportance: 0.7})
+	if _, _, err := g.Refresh(context.Background()); err == nil {
+		t.Fatal("expected an error without a tool model")
+	}
+}
@@ -0,0 +1,60 @@
+package retrieval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/types"
+)
+
+// stageContext bounds a stage by ms milliseconds. ms <= 0 leaves ctx unbounded.
+func stageContext(ctx context.Context, ms int) (context.Context, context.CancelFunc) {
+	if ms <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(ms)*time.Millisecond)
+}
+
+// degradation collects why a retrieval returned less than it normally would.
+// Its methods are safe for concurrent use, and a nil degradation records nothing.
+type degradation struct {
+	mu      sync.Mutex
+	reasons []string
+}
+
+func (d *degradation) add(format string, args ...any) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.reasons = append(d.reasons, fmt.Sprintf(format, args...))
+}
+
+func (d *degradation) list() []string {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.reasons...)
+}
+
+// stageError describes a failed stage for DegradedReasons.
+func stageError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timed out"
+	}
+	return fmt.Sprintf("failed (%v)", err)
+}
+
+// embed embeds one query within the embedding deadline.
+func (r *Retriever) embed(ctx context.Context, c client.EmbeddingClient, model types.Model, text string) ([]float32, error) {
+	ctx, cancel := stageContext(ctx, r.config.EmbedTimeoutMs)
+	defer cancel()
+	return c.Embed(ctx, model, text)
+}
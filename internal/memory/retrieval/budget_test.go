@@ -0,0 +1,123 @@
+package retrieval
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// slowQueryClient answers after delay unless its context ends first.
+type slowQueryClient struct {
+	delay time.Duration
+}
+
+func (c *slowQueryClient) ChatStream(ctx context.Context, model types.Model, query string) (client.StreamResponse, error) {
+	select {
+	case <-time.After(c.delay):
+		return &fakeStream{chunks: []string{"ANSWER: slow answer\nREPHRASE: slow rephrase"}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *slowQueryClient) ChatStreamWithContext(ctx context.Context, model types.Model, systemContext, query string) (client.StreamResponse, error) {
+	return c.ChatStream(ctx, model, query)
+}
+
+func (c *slowQueryClient) ListModels(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+// stuckEmbeddingClient ignores cancellation, like a client without deadline support.
+type stuckEmbeddingClient struct {
+	fakeEmbeddingClient
+	delay time.Duration
+}
+
+func (c *stuckEmbeddingClient) Embed(ctx context.Context, model types.Model, text string) ([]float32, error) {
+	time.Sleep(c.delay)
+	return c.fakeEmbeddingClient.Embed(ctx, model, text)
+}
+
+func newBudgetStore(t *testing.T, model types.Model) *store.Store {
+	t.Helper()
+	s, err := store.NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	item := &MemoryItem{
+		ID: "cpp", Text: "C++ virtual functions enable polymorphism", Source: SourceExplicit,
+		Provider: model.Provider, ModelID: model.ModelID, Dim: 2, Embedding: []float32{1, 0},
+	}
+	if err := s.SaveMemory(item); err != nil {
+		t.Fatalf("save memory: %v", err)
+	}
+	return s
+}
+
+func TestRetrieve_TransformTimeoutFallsBackToRawQuery(t *testing.T) {
+	model := types.Model{Provider: "fake", ModelID: "fake-embed"}
+	cfg := utils.DefaultConfig().Memory
+	cfg.MinSimilarity = 0.1
+	cfg.HistoryTopK = -1
+	cfg.TransformTimeoutMs = 20
+
+	r := NewRetriever(newBudgetStore(t, model), &fakeEmbeddingClient{}, &slowQueryClient{delay: time.Second}, model, types.Model{}, cfg)
+	resp, err := r.RetrieveWithOptions(context.Background(), "C++ polymorphism", RetrieveOptions{Explain: true})
+	if err != nil {
+		t.Fatalf("retrieve: %v", err)
+	}
+
+	if got := resp.Explain.TransformedQueries; len(got) != 1 || got[0] != "C++ polymorphism" {
+		t.Fatalf("expected only the raw query, got %q", got)
+	}
+	if len(resp.Results) == 0 || resp.Results[0].Item.ID != "cpp" {
+		t.Fatalf("expected results from the raw query, got %+v", resp.Results)
+	}
+	if !resp.Degraded || !strings.Contains(strings.Join(resp.DegradedReasons, "|"), "query transformation timed out") {
+		t.Fatalf("expected a degraded response, got %v %q", resp.Degraded, resp.DegradedReasons)
+	}
+	if out := FormatAsText(resp); !strings.Contains(out, "results may be incomplete: ") || !strings.Contains(out, "query transformation timed out") {
+		t.Fatalf("expected a degraded note:\n%s", out)
+	}
+}
+
+func TestRetrieve_LatencyBudgetReturnsPartialResults(t *testing.T) {
+	model := types.Model{Provider: "fake", ModelID: "fake-embed"}
+	cfg := utils.DefaultConfig().Memory
+	cfg.HistoryTopK = -1
+	cfg.LatencyBudgetMs = 50
+
+	r := NewRetriever(newBudgetStore(t, model), &stuckEmbeddingClient{delay: time.Second}, nil, model, types.Model{}, cfg)
+	start := time.Now()
+	resp, err := r.Retrieve(context.Background(), "polymorphism")
+	if err != nil {
+		t.Fatalf("retrieve: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("retrieval took %v, beyond the 50ms budget", elapsed)
+	}
+	// FTS finished in time even though vector search didn't
+	if len(resp.Results) != 1 || resp.Results[0].Source != "fts" {
+		t.Fatalf("expected the FTS result, got %+v", resp.Results)
+	}
+	if !resp.Degraded || !strings.Contains(strings.Join(resp.DegradedReasons, "|"), "vector search did not finish within the 50ms latency budget") {
+		t.Fatalf("expected a degraded response, got %v %q", resp.Degraded, resp.DegradedReasons)
+	}
+
+	cfg.LatencyBudgetMs = -1
+	r = NewRetriever(newBudgetStore(t, model), &fakeEmbeddingClient{}, nil, model, types.Model{}, cfg)
+	if resp, err = r.Retrieve(context.Background(), "polymorphism"); err != nil || resp.Degraded {
+		t.Fatalf("expected a clean retrieval without a budget, got %v %q", err, resp.DegradedReasons)
+	}
+}
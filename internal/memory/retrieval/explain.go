@@ -0,0 +1,322 @@
+package retrieval
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/austiecodes/gomor/internal/memory/memtypes"
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+type RetrievalTrace = memtypes.RetrievalTrace
+type ResultTrace = memtypes.ResultTrace
+type ScoreTerm = memtypes.ScoreTerm
+
+// tracer collects a RetrievalTrace while a retrieval runs. Its methods are
+// safe for concurrent use, and a nil tracer records nothing, so stages call
+// it unconditionally. Stages still running after finish, because they ran past
+// the latency budget, are not recorded.
+type tracer struct {
+	mu       sync.Mutex
+	trace    RetrievalTrace
+	fusion   map[string]ResultTrace // per memory ID, before recency and rerank
+	grades   map[string]int
+	finished bool
+}
+
+func (t *tracer) record(f func(tr *RetrievalTrace)) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.finished {
+		f(&t.trace)
+	}
+}
+
+// stage records the time since start under name. Use it with defer:
+//
+//	defer opts.tracer.stage("fts_search", time.Now())
+func (t *tracer) stage(name string, start time.Time) {
+	elapsed := time.Since(start)
+	t.record(func(tr *RetrievalTrace) {
+		tr.Timings = append(tr.Timings, memtypes.StageTiming{
+			Stage:      name,
+			DurationMs: float64(elapsed.Microseconds()) / 1000,
+		})
+	})
+}
+
+func (t *tracer) transformed(queries []string) {
+	t.record(func(tr *RetrievalTrace) {
+		tr.TransformedQueries = append([]string(nil), queries...)
+	})
+}
+
+func (t *tracer) vectorSearch(query string, model types.Model, results []SearchResult, err error) {
+	t.record(func(tr *RetrievalTrace) {
+		vt := memtypes.VectorTrace{Query: query, Model: model.Provider + "/" + model.ModelID, Hits: []memtypes.TraceHit{}}
+		for _, res := range results {
+			vt.Hits = append(vt.Hits, memtypes.TraceHit{ID: res.Item.ID, Text: res.Item.Text, Score: res.Similarity})
+		}
+		if err != nil {
+			vt.Error = err.Error()
+		}
+		tr.VectorSearches = append(tr.VectorSearches, vt)
+	})
+}
+
+func (t *tracer) ftsSearch(strategy, input, expression string, results []MemoryFTSResult, err error) {
+	t.record(func(tr *RetrievalTrace) {
+		ft := memtypes.FTSTrace{Strategy: strategy, Input: input, Expression: expression, Hits: []memtypes.TraceHit{}}
+		for _, res := range results {
+			ft.Hits = append(ft.Hits, memtypes.TraceHit{ID: res.Item.ID, Text: res.Item.Text, Score: res.Rank})
+		}
+		if err != nil {
+			ft.Error = err.Error()
+		}
+		tr.FTSSearches = append(tr.FTSSearches, ft)
+	})
+}
+
+// fused records how fuser scored each candidate, before recency is applied.
+func (t *tracer) fused(fuser Fuser, vectorResults []SearchResult, ftsResults []MemoryFTSResult, results []UnifiedResult) {
+	if t == nil {
+		return
+	}
+
+	var terms map[string]ResultTrace
+	if e, ok := fuser.(fusionExplainer); ok {
+		terms = e.explain(vectorResults, ftsResults)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.trace.FusionStrategy = fuserName(fuser)
+	t.fusion = make(map[string]ResultTrace, len(results))
+	for _, res := range results {
+		rt := terms[res.Item.ID]
+		rt.FusedScore = res.Score
+		t.fusion[res.Item.ID] = rt
+	}
+}
+
+func (t *tracer) reranked(grades map[string]int) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.grades = grades
+}
+
+// finish builds the per-result traces for the final results and returns the trace.
+func (t *tracer) finish(results []UnifiedResult) *RetrievalTrace {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.finished = true
+
+	t.trace.Results = make([]ResultTrace, 0, len(results))
+	for _, res := range results {
+		rt := t.fusion[res.Item.ID]
+		rt.ID = res.Item.ID
+		rt.Text = res.Item.Text
+		rt.Source = res.Source
+		rt.Score = res.Score
+		rt.RecencyFactor = 1
+		if rt.FusedScore > 0 {
+			rt.RecencyFactor = res.Score / rt.FusedScore
+		}
+		if grade, ok := t.grades[res.Item.ID]; ok {
+			rt.RerankGrade = &grade
+		}
+		t.trace.Results = append(t.trace.Results, rt)
+	}
+	return &t.trace
+}
+
+// fusionExplainer is implemented by fusers that can break a fused score into
+// the terms that produced it. The returned traces are keyed by memory ID and
+// have their list positions and terms set.
+type fusionExplainer interface {
+	explain(vectorResults []SearchResult, ftsResults []MemoryFTSResult) map[string]ResultTrace
+}
+
+func fuserName(f Fuser) string {
+	switch f.(type) {
+	case LegacyFuser:
+		return utils.FusionStrategyLegacy
+	case WeightedFuser:
+		return utils.FusionStrategyWeighted
+	case RRFFuser:
+		return utils.FusionStrategyRRF
+	default:
+		return fmt.Sprintf("%T", f)
+	}
+}
+
+// explainCandidates runs terms over the merged candidates and collects the traces.
+func explainCandidates(vectorResults []SearchResult, ftsResults []MemoryFTSResult, terms func(c *fusionCandidate) []ScoreTerm) map[string]ResultTrace {
+	candidates := mergeResults(vectorResults, ftsResults)
+	traces := make(map[string]ResultTrace, len(candidates))
+	for _, c := range candidates {
+		traces[c.result.Item.ID] = ResultTrace{
+			VectorPos: c.vectorPos,
+			FTSPos:    c.ftsPos,
+			Terms:     terms(c),
+		}
+	}
+	return traces
+}
+
+func (LegacyFuser) explain(vectorResults []SearchResult, ftsResults []MemoryFTSResult) map[string]ResultTrace {
+	return explainCandidates(vectorResults, ftsResults, func(c *fusionCandidate) []ScoreTerm {
+		ur := c.result
+		switch ur.Source {
+		case "vector":
+			return []ScoreTerm{{Name: "vector similarity", Value: ur.VectorScore}}
+		case "fts":
+			return []ScoreTerm{{Name: "fts 1+rank/20", Value: calculateUnifiedScore(ur)}}
+		}
+		vector := 0.6 * ur.VectorScore
+		fts := 0.4 * (calculateUnifiedScore(&UnifiedResult{Source: "fts", FTSRank: ur.FTSRank}))
+		return []ScoreTerm{
+			{Name: "vector 0.6*similarity", Value: vector},
+			{Name: "fts 0.4*(1+rank/20)", Value: fts},
+			{Name: "both-lists boost x1.2, capped at 1", Value: calculateUnifiedScore(ur) - vector - fts},
+		}
+	})
+}
+
+func (f WeightedFuser) explain(vectorResults []SearchResult, ftsResults []MemoryFTSResult) map[string]ResultTrace {
+	vMin, vMax := minMax(len(vectorResults), func(i int) float64 { return vectorResults[i].Similarity })
+	fMin, fMax := minMax(len(ftsResults), func(i int) float64 { return -ftsResults[i].Rank })
+
+	total := f.VectorWeight + f.FTSWeight
+	if total <= 0 {
+		total = 1
+	}
+	return explainCandidates(vectorResults, ftsResults, func(c *fusionCandidate) []ScoreTerm {
+		var terms []ScoreTerm
+		if c.vectorPos > 0 {
+			terms = append(terms, ScoreTerm{
+				Name:  fmt.Sprintf("vector %g*norm(similarity)/%g", f.VectorWeight, total),
+				Value: f.VectorWeight * normalize(c.result.VectorScore, vMin, vMax) / total,
+			})
+		}
+		if c.ftsPos > 0 {
+			terms = append(terms, ScoreTerm{
+				Name:  fmt.Sprintf("fts %g*norm(-rank)/%g", f.FTSWeight, total),
+				Value: f.FTSWeight * normalize(-c.result.FTSRank, fMin, fMax) / total,
+			})
+		}
+		return terms
+	})
+}
+
+func (f RRFFuser) explain(vectorResults []SearchResult, ftsResults []MemoryFTSResult) map[string]ResultTrace {
+	k := f.K
+	if k <= 0 {
+		k = 60
+	}
+	maxScore := (f.VectorWeight + f.FTSWeight) / (k + 1)
+	if maxScore <= 0 {
+		maxScore = 1
+	}
+	return explainCandidates(vectorResults, ftsResults, func(c *fusionCandidate) []ScoreTerm {
+		var terms []ScoreTerm
+		if c.vectorPos > 0 {
+			terms = append(terms, ScoreTerm{
+				Name:  fmt.Sprintf("vector %g/(%g+%d)/max", f.VectorWeight, k, c.vectorPos),
+				Value: f.VectorWeight / (k + float64(c.vectorPos)) / maxScore,
+			})
+		}
+		if c.ftsPos > 0 {
+			terms = append(terms, ScoreTerm{
+				Name:  fmt.Sprintf("fts %g/(%g+%d)/max", f.FTSWeight, k, c.ftsPos),
+				Value: f.FTSWeight / (k + float64(c.ftsPos)) / maxScore,
+			})
+		}
+		return terms
+	})
+}
+
+// FormatTrace renders an explain trace as readable text.
+func FormatTrace(trace *RetrievalTrace) string {
+	if trace == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Explain:\n\nTransformed queries:\n")
+	for i, q := range trace.TransformedQueries {
+		sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, q))
+	}
+
+	sb.WriteString("\nVector searches:\n")
+	for _, vt := range trace.VectorSearches {
+		sb.WriteString(fmt.Sprintf("  %q (%s): %d hits\n", vt.Query, vt.Model, len(vt.Hits)))
+		if vt.Error != "" {
+			sb.WriteString(fmt.Sprintf("    error: %s\n", vt.Error))
+		}
+		for _, h := range vt.Hits {
+			sb.WriteString(fmt.Sprintf("    %.4f  %s  %s\n", h.Score, h.ID, traceText(h.Text)))
+		}
+	}
+
+	sb.WriteString("\nFTS searches:\n")
+	for _, ft := range trace.FTSSearches {
+		sb.WriteString(fmt.Sprintf("  %s %q -> %s: %d hits\n", ft.Strategy, ft.Input, ftsExpression(ft.Expression), len(ft.Hits)))
+		if ft.Error != "" {
+			sb.WriteString(fmt.Sprintf("    error: %s\n", ft.Error))
+		}
+		for _, h := range ft.Hits {
+			sb.WriteString(fmt.Sprintf("    %.4f  %s  %s\n", h.Score, h.ID, traceText(h.Text)))
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("\nScores (%s fusion):\n", trace.FusionStrategy))
+	for i, rt := range trace.Results {
+		sb.WriteString(fmt.Sprintf("  %d. %s [%.4f] %s\n", i+1, rt.ID, rt.Score, traceText(rt.Text)))
+		sb.WriteString(fmt.Sprintf("     source %s, vector #%s, fts #%s\n", rt.Source, tracePos(rt.VectorPos), tracePos(rt.FTSPos)))
+		for _, term := range rt.Terms {
+			sb.WriteString(fmt.Sprintf("     + %.4f  %s\n", term.Value, term.Name))
+		}
+		sb.WriteString(fmt.Sprintf("     = %.4f fused x %.4f recency\n", rt.FusedScore, rt.RecencyFactor))
+		if rt.RerankGrade != nil {
+			sb.WriteString(fmt.Sprintf("     rerank grade %d\n", *rt.RerankGrade))
+		}
+	}
+
+	sb.WriteString("\nTimings:\n")
+	for _, st := range trace.Timings {
+		sb.WriteString(fmt.Sprintf("  %-16s %8.1fms\n", st.Stage, st.DurationMs))
+	}
+	return sb.String()
+}
+
+func ftsExpression(expr string) string {
+	if expr == "" {
+		return "(no searchable words)"
+	}
+	return expr
+}
+
+func tracePos(pos int) string {
+	if pos == 0 {
+		return "-"
+	}
+	return fmt.Sprint(pos)
+}
+
+// traceText shortens memory text to one line for the trace.
+func traceText(s string) string {
+	return strings.TrimSuffix(truncateRunes(strings.Join(strings.Fields(s), " "), 60), "\n")
+}
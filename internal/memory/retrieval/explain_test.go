@@ -0,0 +1,122 @@
+package retrieval
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+func TestRetriever_Explain(t *testing.T) {
+	s, err := store.NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	model := types.Model{Provider: "fake", ModelID: "fake-embed"}
+	for _, m := range []*MemoryItem{
+		{ID: "cpp", Text: "C++ virtual functions enable polymorphism", Embedding: []float32{1, 0}},
+		{ID: "go", Text: "Go interfaces are satisfied implicitly", Embedding: []float32{0, 1}},
+	} {
+		m.Source, m.Provider, m.ModelID, m.Dim, m.CreatedAt = SourceExplicit, model.Provider, model.ModelID, 2, time.Now()
+		if err := s.SaveMemory(m); err != nil {
+			t.Fatalf("save memory: %v", err)
+		}
+	}
+
+	cfg := utils.DefaultConfig().Memory
+	cfg.MinSimilarity = 0.1
+	cfg.FusionStrategy = utils.FusionStrategyRRF
+	r := NewRetriever(s, &fakeEmbeddingClient{}, &fakeQueryClient{}, model, types.Model{}, cfg)
+
+	resp, err := r.Retrieve(context.Background(), "C++ polymorphism")
+	if err != nil {
+		t.Fatalf("retrieve: %v", err)
+	}
+	if resp.Explain != nil {
+		t.Fatalf("trace should only be attached in explain mode")
+	}
+
+	resp, err = r.RetrieveWithOptions(context.Background(), "C++ polymorphism", RetrieveOptions{Explain: true})
+	if err != nil {
+		t.Fatalf("retrieve: %v", err)
+	}
+	trace := resp.Explain
+	if trace == nil {
+		t.Fatalf("expected a trace")
+	}
+
+	// Original query plus the fake tool model's answer and rephrasing
+	if len(trace.TransformedQueries) != 3 || len(trace.VectorSearches) != 3 {
+		t.Fatalf("expected 3 transformed and embedded queries, got %+v", trace)
+	}
+	if trace.VectorSearches[0].Model != "fake/fake-embed" || len(trace.VectorSearches[0].Hits) == 0 {
+		t.Fatalf("unexpected vector trace: %+v", trace.VectorSearches[0])
+	}
+	if len(trace.FTSSearches) == 0 || trace.FTSSearches[0].Expression != "polymorphism" {
+		t.Fatalf("unexpected fts trace: %+v", trace.FTSSearches)
+	}
+	if trace.FusionStrategy != utils.FusionStrategyRRF {
+		t.Fatalf("unexpected fusion strategy %q", trace.FusionStrategy)
+	}
+
+	if len(trace.Results) != len(resp.Results) {
+		t.Fatalf("expected a trace per result, got %d for %d", len(trace.Results), len(resp.Results))
+	}
+	for _, rt := range trace.Results {
+		var sum float64
+		for _, term := range rt.Terms {
+			sum += term.Value
+		}
+		if !approxEqual(sum, rt.FusedScore) || !approxEqual(rt.FusedScore*rt.RecencyFactor, rt.Score) {
+			t.Errorf("%s: terms %+v don't add up to %f (score %f)", rt.ID, rt.Terms, rt.FusedScore, rt.Score)
+		}
+	}
+	top := trace.Results[0]
+	if top.ID != "cpp" || top.VectorPos != 1 || top.FTSPos != 1 {
+		t.Fatalf("unexpected top result trace: %+v", top)
+	}
+
+	stages := make(map[string]bool)
+	for _, st := range trace.Timings {
+		stages[st.Stage] = true
+	}
+	for _, stage := range []string{"query_transform", "vector_search", "fts_search", "fusion", "total"} {
+		if !stages[stage] {
+			t.Errorf("missing timing for %s: %+v", stage, trace.Timings)
+		}
+	}
+
+	out := FormatTrace(trace)
+	for _, want := range []string{"Transformed queries:", `direct "C++ polymorphism" -> polymorphism`, "rrf fusion", "Timings:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("formatted trace missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFusers_ExplainTermsMatchScores(t *testing.T) {
+	vector, fts := fusionInputs()
+	for _, fuser := range []Fuser{
+		LegacyFuser{},
+		WeightedFuser{VectorWeight: 0.7, FTSWeight: 0.3},
+		RRFFuser{K: 60, VectorWeight: 0.5, FTSWeight: 0.5},
+	} {
+		traces := fuser.(fusionExplainer).explain(vector, fts)
+		for _, res := range fuser.Fuse(vector, fts) {
+			var sum float64
+			for _, term := range traces[res.Item.ID].Terms {
+				sum += term.Value
+			}
+			if !approxEqual(sum, res.Score) {
+				t.Errorf("%s %s: terms sum to %f, score is %f", fuserName(fuser), res.Item.ID, sum, res.Score)
+			}
+		}
+	}
+}
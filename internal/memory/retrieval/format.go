@@ -0,0 +1,171 @@
+package retrieval
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// minTruncatedEntry is the smallest space worth filling with a cut-off entry;
+// with less room left the entry is dropped instead.
+const minTruncatedEntry = 40
+
+// FormatAsText formats the retrieval results as readable text.
+func FormatAsText(resp *RetrievalResponse) string {
+	return FormatAsTextWithLimit(resp, 0)
+}
+
+// FormatAsTextWithLimit formats memories followed by history snippets in at
+// most maxChars characters (0 means no limit). Entries are packed in rank
+// order with memories first; once one doesn't fit, it is cut off if there is
+// enough room left, and the rest are dropped with a note saying how many.
+// Memories skipped by vector search because of a model mismatch, and stages
+// that failed or ran out of time, are noted too.
+func FormatAsTextWithLimit(resp *RetrievalResponse, maxChars int) string {
+	if resp == nil {
+		return "No memories found."
+	}
+	note := mismatchNote(resp.Mismatched) + degradedNote(resp.DegradedReasons)
+	if len(resp.Results) == 0 && len(resp.HistorySnippets) == 0 {
+		return "No memories found." + note
+	}
+
+	memories := make([]string, len(resp.Results))
+	for i, r := range resp.Results {
+		memories[i] = formatMemoryEntry(i+1, r)
+	}
+	history := make([]string, len(resp.HistorySnippets))
+	for i, h := range resp.HistorySnippets {
+		history[i] = formatHistoryEntry(i+1, h)
+	}
+
+	full := renderSections(memories, history) + note
+	if maxChars <= 0 || utf8.RuneCountInString(full) <= maxChars {
+		return full
+	}
+
+	// Reserve room for the omission note, sized for the worst case
+	total := len(memories) + len(history)
+	budget := maxChars - utf8.RuneCountInString(omittedNote(total, total, maxChars)+note)
+	if budget < minTruncatedEntry {
+		return truncateRunes(full, maxChars)
+	}
+
+	var keptMemories, keptHistory []string
+	render := func() string { return renderSections(keptMemories, keptHistory) }
+	sections := []struct {
+		entries []string
+		kept    *[]string
+	}{
+		{memories, &keptMemories},
+		{history, &keptHistory},
+	}
+
+pack:
+	for _, sec := range sections {
+		for _, entry := range sec.entries {
+			*sec.kept = append(*sec.kept, entry)
+			if utf8.RuneCountInString(render()) <= budget {
+				continue
+			}
+
+			// Doesn't fit: cut it off if there is enough room, then stop
+			last := len(*sec.kept) - 1
+			(*sec.kept)[last] = ""
+			if room := budget - utf8.RuneCountInString(render()); room >= minTruncatedEntry {
+				(*sec.kept)[last] = truncateRunes(entry, room)
+			} else {
+				*sec.kept = (*sec.kept)[:last]
+			}
+			break pack
+		}
+	}
+
+	omitted := total - len(keptMemories) - len(keptHistory)
+	return renderSections(keptMemories, keptHistory) + note + omittedNote(omitted, total, maxChars)
+}
+
+func formatMemoryEntry(n int, r UnifiedResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d. [%.2f] %s\n", n, r.Score, r.Item.Text))
+	if len(r.Item.Tags) > 0 {
+		sb.WriteString(fmt.Sprintf("   Tags: %s\n", strings.Join(r.Item.Tags, ", ")))
+	}
+	sb.WriteString(fmt.Sprintf("   Source: %s\n", r.Source))
+	if r.Item.SourcePath != "" {
+		sb.WriteString(fmt.Sprintf("   Document: %s (chunk %d)\n", r.Item.SourcePath, r.Item.ChunkIndex+1))
+	}
+	return sb.String()
+}
+
+func formatHistoryEntry(n int, h HistorySearchResult) string {
+	text := h.Snippet
+	if text == "" {
+		text = h.Item.Content
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	return fmt.Sprintf("%d. [%s] %s: %s\n", n, h.Item.CreatedAt.Format("2006-01-02"), h.Item.Role, text)
+}
+
+// renderSections joins formatted entries under their section headers.
+func renderSections(memories, history []string) string {
+	var sb strings.Builder
+	if len(memories) > 0 {
+		sb.WriteString(fmt.Sprintf("Found %d memories:\n\n", len(memories)))
+		for _, e := range memories {
+			sb.WriteString(e)
+		}
+	} else {
+		sb.WriteString("No memories found.\n")
+	}
+	if len(history) > 0 {
+		sb.WriteString("\nRelated conversation history:\n\n")
+		for _, e := range history {
+			sb.WriteString(e)
+		}
+	}
+	return sb.String()
+}
+
+func omittedNote(omitted, total, maxChars int) string {
+	return fmt.Sprintf("\n(%d of %d results omitted to stay within %d characters)\n", omitted, total, maxChars)
+}
+
+// mismatchNote tells the reader that some memories were only searched by text.
+func mismatchNote(mismatched []ModelMismatch) string {
+	if len(mismatched) == 0 {
+		return ""
+	}
+	var count int
+	models := make([]string, 0, len(mismatched))
+	for _, m := range mismatched {
+		count += m.Count
+		name := m.Provider + "/" + m.ModelID
+		if !slices.Contains(models, name) {
+			models = append(models, name)
+		}
+	}
+	return fmt.Sprintf("\nNote: %d memories embedded with %s were only searched by text; run 'gomor doctor --fix' to re-embed them.\n",
+		count, strings.Join(models, ", "))
+}
+
+// degradedNote tells the reader that the results may be incomplete.
+func degradedNote(reasons []string) string {
+	if len(reasons) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\nNote: results may be incomplete: %s.\n", strings.Join(reasons, "; "))
+}
+
+// truncateRunes cuts s to at most n characters, marking the cut with "...".
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	if n <= 4 {
+		return string(runes[:max(n, 0)])
+	}
+	return strings.TrimRight(string(runes[:n-4]), " \n") + "...\n"
+}
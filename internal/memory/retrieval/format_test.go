@@ -0,0 +1,127 @@
+package retrieval
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/austiecodes/gomor/internal/memory/memtypes"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+func formatTestResponse() *RetrievalResponse {
+	resp := &RetrievalResponse{Query: "editor"}
+	for i := 1; i <= 5; i++ {
+		resp.Results = append(resp.Results, UnifiedResult{
+			Item:   MemoryItem{ID: fmt.Sprint(i), Text: fmt.Sprintf("memory %d: %s", i, strings.Repeat("x", 80)), Tags: []string{"t"}},
+			Score:  1 - float64(i)/10,
+			Source: "both",
+		})
+	}
+	resp.HistorySnippets = []HistorySearchResult{{
+		Item:    memtypes.HistoryItem{Role: "user", Content: "which editor\ndo I use?", CreatedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+		Snippet: "which >>>editor<<< do I use?",
+	}}
+	return resp
+}
+
+func TestFormatAsText_IncludesHistory(t *testing.T) {
+	out := FormatAsText(formatTestResponse())
+
+	if !strings.HasPrefix(out, "Found 5 memories:") {
+		t.Fatalf("unexpected header:\n%s", out)
+	}
+	if !strings.Contains(out, "Related conversation history:\n\n1. [2025-01-02] user: which >>>editor<<< do I use?\n") {
+		t.Fatalf("missing history snippet:\n%s", out)
+	}
+	if FormatAsText(&RetrievalResponse{}) != "No memories found." {
+		t.Fatalf("unexpected empty output")
+	}
+}
+
+func TestFormatAsTextWithLimit(t *testing.T) {
+	resp := formatTestResponse()
+	full := FormatAsText(resp)
+
+	if got := FormatAsTextWithLimit(resp, 0); got != full {
+		t.Fatalf("limit 0 should not truncate")
+	}
+	if got := FormatAsTextWithLimit(resp, len(full)); got != full {
+		t.Fatalf("output that fits should not change")
+	}
+
+	for _, limit := range []int{600, 350, 120, 60} {
+		out := FormatAsTextWithLimit(resp, limit)
+		if n := utf8.RuneCountInString(out); n > limit {
+			t.Errorf("limit %d: output has %d characters:\n%s", limit, n, out)
+		}
+		if !strings.Contains(out, "memory 1") {
+			t.Errorf("limit %d: top memory should always be kept:\n%s", limit, out)
+		}
+	}
+
+	// Memories take priority over history snippets
+	out := FormatAsTextWithLimit(resp, 350)
+	if strings.Contains(out, "Related conversation history") {
+		t.Fatalf("history should be dropped before memories:\n%s", out)
+	}
+	if !strings.Contains(out, "results omitted to stay within 350 characters") {
+		t.Fatalf("expected an omission note:\n%s", out)
+	}
+}
+
+func TestRetriever_IncludesHistorySnippets(t *testing.T) {
+	s, err := store.NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	old := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, h := range []*memtypes.HistoryItem{
+		{Role: "user", Content: "my new keyboard uses brown switches", CreatedAt: time.Now()},
+		{Role: "assistant", Content: "noted the keyboard layout preference", CreatedAt: old},
+		{Role: "user", Content: "unrelated chatter about lunch", CreatedAt: time.Now()},
+	} {
+		if err := s.SaveHistory(h); err != nil {
+			t.Fatalf("save history: %v", err)
+		}
+	}
+
+	cfg := utils.DefaultConfig().Memory
+	r := NewRetriever(s, &fakeEmbeddingClient{}, nil, types.Model{}, types.Model{}, cfg)
+
+	resp, err := r.Retrieve(context.Background(), "keyboard")
+	if err != nil {
+		t.Fatalf("retrieve: %v", err)
+	}
+	if len(resp.HistorySnippets) != 2 {
+		t.Fatalf("expected 2 history snippets, got %+v", resp.HistorySnippets)
+	}
+
+	resp, err = r.RetrieveWithOptions(context.Background(), "keyboard", RetrieveOptions{
+		Filter: MemoryFilter{CreatedAfter: old.AddDate(0, 1, 0)},
+	})
+	if err != nil {
+		t.Fatalf("retrieve: %v", err)
+	}
+	if len(resp.HistorySnippets) != 1 || resp.HistorySnippets[0].Item.Role != "user" {
+		t.Fatalf("date filter should apply to history, got %+v", resp.HistorySnippets)
+	}
+
+	resp, err = r.RetrieveWithOptions(context.Background(), "keyboard", RetrieveOptions{
+		Filter: MemoryFilter{Tags: []string{"hardware"}},
+	})
+	if err != nil {
+		t.Fatalf("retrieve: %v", err)
+	}
+	if len(resp.HistorySnippets) != 0 {
+		t.Fatalf("tag filter should exclude history, got %+v", resp.HistorySnippets)
+	}
+}
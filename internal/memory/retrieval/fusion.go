@@ -0,0 +1,195 @@
+package retrieval
+
+import (
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// Fuser combines vector and FTS results into a single scored list.
+// Implementations set Score (higher is better, 0-1) and Source on every result;
+// sorting and truncation are left to the caller.
+type Fuser interface {
+	Fuse(vectorResults []SearchResult, ftsResults []MemoryFTSResult) []UnifiedResult
+}
+
+// NewFuser returns the fuser selected by the memory config.
+func NewFuser(config utils.MemoryConfig) Fuser {
+	switch config.FusionStrategy {
+	case utils.FusionStrategyRRF:
+		return RRFFuser{K: float64(config.RRFK), VectorWeight: config.VectorWeight, FTSWeight: config.FTSWeight}
+	case utils.FusionStrategyWeighted:
+		return WeightedFuser{VectorWeight: config.VectorWeight, FTSWeight: config.FTSWeight}
+	default:
+		return LegacyFuser{}
+	}
+}
+
+// fusionCandidate tracks a merged result with its 1-based position in each
+// input list (0 if absent).
+type fusionCandidate struct {
+	result    *UnifiedResult
+	vectorPos int
+	ftsPos    int
+}
+
+// mergeResults merges both result lists by memory ID, keeping first-seen order
+// so that ties are broken deterministically.
+func mergeResults(vectorResults []SearchResult, ftsResults []MemoryFTSResult) []*fusionCandidate {
+	byID := make(map[string]*fusionCandidate)
+	var candidates []*fusionCandidate
+
+	for i, vr := range vectorResults {
+		if _, ok := byID[vr.Item.ID]; ok {
+			continue
+		}
+		c := &fusionCandidate{
+			result: &UnifiedResult{
+				Item:        vr.Item,
+				VectorScore: vr.Similarity,
+				Source:      "vector",
+			},
+			vectorPos: i + 1,
+		}
+		byID[vr.Item.ID] = c
+		candidates = append(candidates, c)
+	}
+
+	for i, fr := range ftsResults {
+		if c, ok := byID[fr.Item.ID]; ok {
+			if c.ftsPos != 0 {
+				continue
+			}
+			// Memory found in both - mark as "both"
+			c.result.Source = "both"
+			c.result.FTSRank = fr.Rank
+			c.result.Snippet = fr.Snippet
+			c.ftsPos = i + 1
+			continue
+		}
+		c := &fusionCandidate{
+			result: &UnifiedResult{
+				Item:    fr.Item,
+				FTSRank: fr.Rank,
+				Snippet: fr.Snippet,
+				Source:  "fts",
+			},
+			ftsPos: i + 1,
+		}
+		byID[fr.Item.ID] = c
+		candidates = append(candidates, c)
+	}
+
+	return candidates
+}
+
+func collectResults(candidates []*fusionCandidate) []UnifiedResult {
+	results := make([]UnifiedResult, len(candidates))
+	for i, c := range candidates {
+		results[i] = *c.result
+	}
+	return results
+}
+
+// LegacyFuser reproduces the original fixed weighting: vector similarity as is,
+// FTS rank mapped from [-20, 0] to [0, 1], and a 1.2 boost for matches in both.
+type LegacyFuser struct{}
+
+func (LegacyFuser) Fuse(vectorResults []SearchResult, ftsResults []MemoryFTSResult) []UnifiedResult {
+	candidates := mergeResults(vectorResults, ftsResults)
+	for _, c := range candidates {
+		c.result.Score = calculateUnifiedScore(c.result)
+	}
+	return collectResults(candidates)
+}
+
+// WeightedFuser min-max normalizes vector similarities and FTS ranks within
+// each result list and combines them linearly. A memory missing from one list
+// contributes 0 for that side.
+type WeightedFuser struct {
+	VectorWeight float64
+	FTSWeight    float64
+}
+
+func (f WeightedFuser) Fuse(vectorResults []SearchResult, ftsResults []MemoryFTSResult) []UnifiedResult {
+	candidates := mergeResults(vectorResults, ftsResults)
+
+	vMin, vMax := minMax(len(vectorResults), func(i int) float64 { return vectorResults[i].Similarity })
+	// FTS rank is lower-is-better, so negate it before normalizing.
+	fMin, fMax := minMax(len(ftsResults), func(i int) float64 { return -ftsResults[i].Rank })
+
+	total := f.VectorWeight + f.FTSWeight
+	for _, c := range candidates {
+		var score float64
+		if c.vectorPos > 0 {
+			score += f.VectorWeight * normalize(c.result.VectorScore, vMin, vMax)
+		}
+		if c.ftsPos > 0 {
+			score += f.FTSWeight * normalize(-c.result.FTSRank, fMin, fMax)
+		}
+		if total > 0 {
+			score /= total
+		}
+		c.result.Score = score
+	}
+	return collectResults(candidates)
+}
+
+// RRFFuser implements weighted reciprocal rank fusion: each list contributes
+// weight / (K + rank). Scores are divided by the best achievable score so that
+// a memory ranked first in both lists scores 1.
+type RRFFuser struct {
+	K            float64
+	VectorWeight float64
+	FTSWeight    float64
+}
+
+func (f RRFFuser) Fuse(vectorResults []SearchResult, ftsResults []MemoryFTSResult) []UnifiedResult {
+	candidates := mergeResults(vectorResults, ftsResults)
+
+	k := f.K
+	if k <= 0 {
+		k = 60
+	}
+	maxScore := (f.VectorWeight + f.FTSWeight) / (k + 1)
+
+	for _, c := range candidates {
+		var score float64
+		if c.vectorPos > 0 {
+			score += f.VectorWeight / (k + float64(c.vectorPos))
+		}
+		if c.ftsPos > 0 {
+			score += f.FTSWeight / (k + float64(c.ftsPos))
+		}
+		if maxScore > 0 {
+			score /= maxScore
+		}
+		c.result.Score = score
+	}
+	return collectResults(candidates)
+}
+
+// minMax returns the minimum and maximum of n values produced by value.
+func minMax(n int, value func(i int) float64) (float64, float64) {
+	if n == 0 {
+		return 0, 0
+	}
+	lo, hi := value(0), value(0)
+	for i := 1; i < n; i++ {
+		v := value(i)
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	return lo, hi
+}
+
+// normalize maps v from [lo, hi] to [0, 1]. A degenerate range maps to 1,
+// since the only value present is also the best one.
+func normalize(v, lo, hi float64) float64 {
+	if hi <= lo {
+		return 1
+	}
+	return (v - lo) / (hi - lo)
+}
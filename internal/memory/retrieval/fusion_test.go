@@ -0,0 +1,125 @@
+package retrieval
+
+import (
+	"math"
+	"testing"
+
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+func fusionInputs() ([]SearchResult, []MemoryFTSResult) {
+	vector := []SearchResult{
+		{Item: MemoryItem{ID: "a"}, Similarity: 0.9},
+		{Item: MemoryItem{ID: "b"}, Similarity: 0.7},
+		{Item: MemoryItem{ID: "c"}, Similarity: 0.5},
+	}
+	fts := []MemoryFTSResult{
+		{Item: MemoryItem{ID: "b"}, Rank: -8},
+		{Item: MemoryItem{ID: "d"}, Rank: -4},
+		{Item: MemoryItem{ID: "a"}, Rank: -2},
+	}
+	return vector, fts
+}
+
+func scoresByID(results []UnifiedResult) map[string]UnifiedResult {
+	m := make(map[string]UnifiedResult, len(results))
+	for _, r := range results {
+		m[r.Item.ID] = r
+	}
+	return m
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestRRFFuser(t *testing.T) {
+	vector, fts := fusionInputs()
+	results := scoresByID(RRFFuser{K: 60, VectorWeight: 1, FTSWeight: 1}.Fuse(vector, fts))
+
+	if len(results) != 4 {
+		t.Fatalf("expected 4 merged results, got %d", len(results))
+	}
+	if results["a"].Source != "both" || results["d"].Source != "fts" || results["c"].Source != "vector" {
+		t.Fatalf("unexpected sources: %+v", results)
+	}
+
+	// a: 1/61 + 1/63, b: 1/62 + 1/61, normalized by 2/61
+	wantA := (1.0/61 + 1.0/63) / (2.0 / 61)
+	wantB := (1.0/62 + 1.0/61) / (2.0 / 61)
+	if !approxEqual(results["a"].Score, wantA) || !approxEqual(results["b"].Score, wantB) {
+		t.Fatalf("unexpected scores: a=%f b=%f", results["a"].Score, results["b"].Score)
+	}
+	if results["b"].Score <= results["a"].Score {
+		t.Fatalf("b (ranks 2 and 1) should beat a (ranks 1 and 3)")
+	}
+	if results["c"].Score >= results["b"].Score || results["d"].Score >= results["b"].Score {
+		t.Fatalf("single-list matches should rank below matches in both lists")
+	}
+
+	// A memory ranked first in both lists gets the maximum score of 1.
+	top := RRFFuser{K: 60, VectorWeight: 0.6, FTSWeight: 0.4}.Fuse(vector[:1], fts[2:])
+	if len(top) != 1 || !approxEqual(top[0].Score, 1) {
+		t.Fatalf("expected score 1 for first in both lists, got %+v", top)
+	}
+}
+
+func TestWeightedFuser(t *testing.T) {
+	vector, fts := fusionInputs()
+	results := scoresByID(WeightedFuser{VectorWeight: 0.5, FTSWeight: 0.5}.Fuse(vector, fts))
+
+	// Vector similarities normalize to a=1, b=0.5, c=0; FTS ranks to b=1, d=1/3, a=0.
+	want := map[string]float64{
+		"a": 0.5 * 1,
+		"b": 0.5*0.5 + 0.5*1,
+		"c": 0,
+		"d": 0.5 * (1.0 / 3),
+	}
+	for id, score := range want {
+		if !approxEqual(results[id].Score, score) {
+			t.Errorf("%s: got %f want %f", id, results[id].Score, score)
+		}
+	}
+
+	// A single result normalizes to the top of the range.
+	single := WeightedFuser{VectorWeight: 1, FTSWeight: 0}.Fuse(vector[2:], nil)
+	if len(single) != 1 || !approxEqual(single[0].Score, 1) {
+		t.Fatalf("expected single result to score 1, got %+v", single)
+	}
+}
+
+func TestLegacyFuser(t *testing.T) {
+	vector, fts := fusionInputs()
+	results := scoresByID(LegacyFuser{}.Fuse(vector, fts))
+
+	// b: (0.7*0.6 + (1-8/20)*0.4) * 1.2
+	if want := (0.7*0.6 + 0.6*0.4) * 1.2; !approxEqual(results["b"].Score, want) {
+		t.Errorf("b: got %f want %f", results["b"].Score, want)
+	}
+	if want := 0.5; !approxEqual(results["c"].Score, want) {
+		t.Errorf("c: got %f want %f", results["c"].Score, want)
+	}
+	if want := 1 - 4.0/20; !approxEqual(results["d"].Score, want) {
+		t.Errorf("d: got %f want %f", results["d"].Score, want)
+	}
+}
+
+func TestNewFuser(t *testing.T) {
+	cfg := utils.DefaultConfig().Memory
+
+	if _, ok := NewFuser(cfg).(LegacyFuser); !ok {
+		t.Errorf("default strategy should be legacy")
+	}
+	cfg.FusionStrategy = utils.FusionStrategyRRF
+	if _, ok := NewFuser(cfg).(RRFFuser); !ok {
+		t.Errorf("expected RRFFuser")
+	}
+	cfg.FusionStrategy = utils.FusionStrategyWeighted
+	if _, ok := NewFuser(cfg).(WeightedFuser); !ok {
+		t.Errorf("expected WeightedFuser")
+	}
+	cfg.FusionStrategy = utils.FusionStrategyLegacy
+	if _, ok := NewFuser(cfg).(LegacyFuser); !ok {
+		t.Errorf("expected LegacyFuser")
+	}
+}
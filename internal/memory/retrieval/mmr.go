@@ -0,0 +1,63 @@
+package retrieval
+
+import (
+	"math"
+
+	"github.com/austiecodes/gomor/internal/memory/memutils"
+)
+
+// mmrPoolFactor bounds how many fused candidates MMR considers per selected result.
+const mmrPoolFactor = 3
+
+// mmrRerank selects up to k results by Maximal Marginal Relevance. Each step picks
+// the candidate maximizing lambda*relevance - (1-lambda)*max similarity to the
+// results already selected, so near-duplicates of a chosen memory are pushed down.
+// Relevance is the fused score; similarity is the cosine of the stored embeddings.
+// results must be sorted by score descending.
+func mmrRerank(results []UnifiedResult, lambda float64, k int) []UnifiedResult {
+	if k <= 0 || len(results) == 0 {
+		return nil
+	}
+
+	pool := results
+	if len(pool) > k*mmrPoolFactor {
+		pool = pool[:k*mmrPoolFactor]
+	}
+	if k > len(pool) {
+		k = len(pool)
+	}
+
+	selected := make([]UnifiedResult, 0, k)
+	used := make([]bool, len(pool))
+	// maxSim[i] is the highest similarity of pool[i] to any selected result
+	maxSim := make([]float64, len(pool))
+
+	for len(selected) < k {
+		best := -1
+		bestValue := math.Inf(-1)
+		for i, c := range pool {
+			if used[i] {
+				continue
+			}
+			value := lambda*c.Score - (1-lambda)*maxSim[i]
+			if value > bestValue {
+				best, bestValue = i, value
+			}
+		}
+
+		used[best] = true
+		selected = append(selected, pool[best])
+
+		for i, c := range pool {
+			if used[i] {
+				continue
+			}
+			sim := memutils.CosineSimilarity(c.Item.Embedding, pool[best].Item.Embedding)
+			if sim > maxSim[i] {
+				maxSim[i] = sim
+			}
+		}
+	}
+
+	return selected
+}
@@ -0,0 +1,67 @@
+package retrieval
+
+import (
+	"testing"
+
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// redundantCandidates returns three near-duplicate memories about Go error
+// handling that outscore a single memory about a different, still relevant fact.
+func redundantCandidates() []UnifiedResult {
+	return []UnifiedResult{
+		{Item: MemoryItem{ID: "dup1", Text: "prefers wrapping errors with %w", Embedding: NormalizeVector([]float32{1, 0.01, 0})}, Score: 0.95},
+		{Item: MemoryItem{ID: "dup2", Text: "always wraps errors using %w", Embedding: NormalizeVector([]float32{1, 0.02, 0})}, Score: 0.94},
+		{Item: MemoryItem{ID: "dup3", Text: "uses fmt.Errorf with %w to wrap errors", Embedding: NormalizeVector([]float32{1, 0, 0.02})}, Score: 0.93},
+		{Item: MemoryItem{ID: "diverse", Text: "logs errors with slog at the top level", Embedding: NormalizeVector([]float32{0.3, 1, 0})}, Score: 0.80},
+	}
+}
+
+func TestMMRRerank_PrefersDiverseResults(t *testing.T) {
+	results := mmrRerank(redundantCandidates(), 0.7, 2)
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Item.ID != "dup1" {
+		t.Fatalf("most relevant result should stay first, got %s", results[0].Item.ID)
+	}
+	if results[1].Item.ID != "diverse" {
+		t.Fatalf("expected diverse memory to beat near-duplicates, got %s", results[1].Item.ID)
+	}
+}
+
+func TestMMRRerank_LambdaOneKeepsRelevanceOrder(t *testing.T) {
+	results := mmrRerank(redundantCandidates(), 1, 3)
+
+	want := []string{"dup1", "dup2", "dup3"}
+	for i, id := range want {
+		if results[i].Item.ID != id {
+			t.Fatalf("position %d: got %s want %s", i, results[i].Item.ID, id)
+		}
+	}
+}
+
+func TestRetriever_FuseResultsWithMMR(t *testing.T) {
+	candidates := redundantCandidates()
+	var vector []SearchResult
+	for _, c := range candidates {
+		vector = append(vector, SearchResult{Item: c.Item, Similarity: c.Score})
+	}
+
+	cfg := utils.DefaultConfig().Memory
+	cfg.MemoryTopK = 2
+	cfg.FusionStrategy = utils.FusionStrategyLegacy
+
+	plain := NewRetriever(nil, nil, nil, types.Model{}, types.Model{}, cfg).fuseResults(vector, nil, RetrieveOptions{TopK: cfg.MemoryTopK})
+	if plain[1].Item.ID != "dup2" {
+		t.Fatalf("without MMR the near-duplicate should be second, got %s", plain[1].Item.ID)
+	}
+
+	cfg.MMREnabled = true
+	diverse := NewRetriever(nil, nil, nil, types.Model{}, types.Model{}, cfg).fuseResults(vector, nil, RetrieveOptions{TopK: cfg.MemoryTopK})
+	if len(diverse) != 2 || diverse[1].Item.ID != "diverse" {
+		t.Fatalf("with MMR the diverse memory should be second, got %+v", diverse)
+	}
+}
@@ -0,0 +1,72 @@
+package retrieval
+
+import (
+	"context"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/types"
+)
+
+// EmbeddingClientFactory creates an embedding client for a provider.
+type EmbeddingClientFactory func(provider string) (client.EmbeddingClient, error)
+
+// SetEmbeddingClientFactory lets the retriever embed queries with models other
+// than its own. With mixed_model_search enabled, memories embedded by an older
+// model are then still found by vector search until they are reindexed.
+func (r *Retriever) SetEmbeddingClientFactory(factory EmbeddingClientFactory) {
+	r.clientFactory = factory
+}
+
+// searchOtherModels embeds the queries with each model in mismatched and
+// searches that model's memories, passing the results to collect. It returns
+// the mismatches that are still unsearched, e.g. because the provider is not
+// configured or the memories' dimension doesn't match the model's output.
+func (r *Retriever) searchOtherModels(ctx context.Context, queries []string, mismatched []ModelMismatch, opts RetrieveOptions, collect func([]SearchResult)) []ModelMismatch {
+	clients := make(map[string]client.EmbeddingClient)
+	attempted := make(map[types.Model]bool)
+	searched := make(map[ModelMismatch]bool) // keyed without Count
+
+	for _, m := range mismatched {
+		model := types.Model{Provider: m.Provider, ModelID: m.ModelID}
+		if model == r.embeddingModel || attempted[model] {
+			// Same model means a dimension mismatch, which re-embedding won't fix
+			continue
+		}
+		attempted[model] = true
+
+		embClient, ok := clients[model.Provider]
+		if !ok {
+			var err error
+			if embClient, err = r.clientFactory(model.Provider); err != nil {
+				embClient = nil
+			}
+			clients[model.Provider] = embClient
+		}
+		if embClient == nil {
+			continue
+		}
+
+		for _, q := range queries {
+			embedding, err := r.embed(ctx, embClient, model, q)
+			if err != nil {
+				opts.tracer.vectorSearch(q, model, nil, err)
+				continue
+			}
+			results, _, err := r.store.SearchMemories(embedding, model, opts.TopK, r.config.MinSimilarity, opts.Filter)
+			opts.tracer.vectorSearch(q, model, results, err)
+			if err != nil {
+				continue
+			}
+			searched[ModelMismatch{Provider: m.Provider, ModelID: m.ModelID, Dim: len(embedding)}] = true
+			collect(results)
+		}
+	}
+
+	var remaining []ModelMismatch
+	for _, m := range mismatched {
+		if !searched[ModelMismatch{Provider: m.Provider, ModelID: m.ModelID, Dim: m.Dim}] {
+			remaining = append(remaining, m)
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,100 @@
+package retrieval
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/memory/store"
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// modelEmbeddingClient returns a fixed vector per model, with a different
+// dimension for each, like two real embedding models would.
+type modelEmbeddingClient struct {
+	vectors map[string][]float32
+}
+
+func (c *modelEmbeddingClient) Embed(ctx context.Context, model types.Model, text string) ([]float32, error) {
+	v, ok := c.vectors[model.ModelID]
+	if !ok {
+		return nil, fmt.Errorf("unknown model %s", model.ModelID)
+	}
+	return v, nil
+}
+
+func (c *modelEmbeddingClient) EmbedBatch(ctx context.Context, model types.Model, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, t := range texts {
+		v, err := c.Embed(ctx, model, t)
+		if err != nil {
+			return nil, err
+		}
+		vectors[i] = v
+	}
+	return vectors, nil
+}
+
+func (c *modelEmbeddingClient) Dimensions(model types.Model) int {
+	return len(c.vectors[model.ModelID])
+}
+
+func TestRetriever_ModelMismatch(t *testing.T) {
+	s, err := store.NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	embClient := &modelEmbeddingClient{vectors: map[string][]float32{
+		"embed-v1": {0, 1, 0},
+		"embed-v2": {1, 0},
+	}}
+	for _, m := range []*MemoryItem{
+		{ID: "new", Text: "prefers dark mode", ModelID: "embed-v2", Embedding: []float32{1, 0}},
+		{ID: "old", Text: "uses a split keyboard", ModelID: "embed-v1", Embedding: []float32{0, 1, 0}},
+	} {
+		m.Source, m.Provider, m.Dim = SourceExplicit, "fake", len(m.Embedding)
+		if err := s.SaveMemory(m); err != nil {
+			t.Fatalf("save memory: %v", err)
+		}
+	}
+
+	cfg := utils.DefaultConfig().Memory
+	current := types.Model{Provider: "fake", ModelID: "embed-v2"}
+	query := "what setup do I like"
+
+	// By default, memories from the old model are skipped and reported
+	r := NewRetriever(s, embClient, nil, current, types.Model{}, cfg)
+	resp, err := r.Retrieve(context.Background(), query)
+	if err != nil {
+		t.Fatalf("retrieve: %v", err)
+	}
+	if got := resultIDs(resp.Results); got != "new" {
+		t.Fatalf("expected only the current-model memory, got %s", got)
+	}
+	if len(resp.Mismatched) != 1 || resp.Mismatched[0].ModelID != "embed-v1" || resp.Mismatched[0].Count != 1 {
+		t.Fatalf("expected the old-model memory to be reported, got %+v", resp.Mismatched)
+	}
+	if out := FormatAsText(resp); !strings.Contains(out, "1 memories embedded with fake/embed-v1 were only searched by text") {
+		t.Fatalf("expected a mismatch note:\n%s", out)
+	}
+
+	// Mixed-model search embeds the query with the old model as well
+	cfg.MixedModelSearch = true
+	r = NewRetriever(s, embClient, nil, current, types.Model{}, cfg)
+	r.SetEmbeddingClientFactory(func(provider string) (client.EmbeddingClient, error) {
+		return embClient, nil
+	})
+	resp, err = r.Retrieve(context.Background(), query)
+	if err != nil {
+		t.Fatalf("retrieve: %v", err)
+	}
+	if len(resp.Results) != 2 || len(resp.Mismatched) != 0 {
+		t.Fatalf("expected both memories and no mismatches, got %+v / %+v", resp.Results, resp.Mismatched)
+	}
+}
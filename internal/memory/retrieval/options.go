@@ -0,0 +1,50 @@
+package retrieval
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RetrieveOptions holds per-call overrides for a retrieval. Zero values fall
+// back to the retriever's MemoryConfig.
+type RetrieveOptions struct {
+	Filter   MemoryFilter
+	TopK     int     // maximum number of results; 0 uses memory_top_k
+	MinScore float64 // drop fused results scoring below this (0-1)
+	Explain  bool    // attach a RetrievalTrace to the response
+
+	tracer   *tracer      // set by RetrieveWithOptions when Explain is on
+	degraded *degradation // set by RetrieveWithOptions
+}
+
+// resolveOptions fills in config defaults for unset options.
+func (r *Retriever) resolveOptions(opts RetrieveOptions) RetrieveOptions {
+	if opts.TopK <= 0 {
+		opts.TopK = r.config.MemoryTopK
+	}
+	return opts
+}
+
+// ParseSource validates a memory source name used in a filter.
+func ParseSource(s string) (MemorySource, error) {
+	switch source := MemorySource(strings.ToLower(strings.TrimSpace(s))); source {
+	case "", SourceExplicit, SourceExtracted, SourceDocument:
+		return source, nil
+	default:
+		return "", fmt.Errorf("unknown source %q (expected %s, %s or %s)", s, SourceExplicit, SourceExtracted, SourceDocument)
+	}
+}
+
+// ParseDate parses a filter date given as YYYY-MM-DD (local midnight) or RFC 3339.
+func ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD or RFC 3339)", s)
+	}
+	return t, nil
+}
@@ -0,0 +1,30 @@
+package retrieval
+
+import (
+	"testing"
+
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+func TestRetriever_FuseResultsAppliesOptions(t *testing.T) {
+	vector := []SearchResult{
+		{Item: MemoryItem{ID: "a"}, Similarity: 0.9},
+		{Item: MemoryItem{ID: "b"}, Similarity: 0.7},
+		{Item: MemoryItem{ID: "c"}, Similarity: 0.5},
+	}
+
+	cfg := utils.DefaultConfig().Memory
+	cfg.FusionStrategy = utils.FusionStrategyLegacy
+	r := NewRetriever(nil, nil, nil, types.Model{}, types.Model{}, cfg)
+
+	if got := resultIDs(r.fuseResults(vector, nil, r.resolveOptions(RetrieveOptions{}))); got != "a,b,c" {
+		t.Fatalf("default options: got %s", got)
+	}
+	if got := resultIDs(r.fuseResults(vector, nil, r.resolveOptions(RetrieveOptions{TopK: 1}))); got != "a" {
+		t.Fatalf("top_k 1: got %s", got)
+	}
+	if got := resultIDs(r.fuseResults(vector, nil, r.resolveOptions(RetrieveOptions{MinScore: 0.6}))); got != "a,b" {
+		t.Fatalf("min_score 0.6: got %s", got)
+	}
+}
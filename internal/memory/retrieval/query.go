@@ -0,0 +1,183 @@
+package retrieval
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Query qualifiers understood by ParseQuery and ParseQueryStrict.
+const (
+	QualifierTag      = "tag"
+	QualifierSource   = "source"
+	QualifierAfter    = "after"
+	QualifierBefore   = "before"
+	QualifierLimit    = "limit"
+	QualifierMinScore = "minscore"
+)
+
+var qualifierNames = []string{QualifierTag, QualifierSource, QualifierAfter, QualifierBefore, QualifierLimit, QualifierMinScore}
+
+// ParsedQuery is a search query split into free text and structured options.
+type ParsedQuery struct {
+	Text    string
+	Options RetrieveOptions
+}
+
+// ParseQuery extracts inline qualifiers such as
+//
+//	tag:go source:explicit after:2025-01-01 error handling
+//
+// into retrieve options and returns the remaining words as the search text.
+// tag may be repeated or given a comma-separated list; all tags must match.
+// Values containing spaces can be double-quoted (tag:"code review"), and a
+// quoted word ("note:this") is always treated as text. Words with a colon
+// that aren't qualifiers, like "error:", URLs or times, are left in the text,
+// and so are qualifier names without a value. This suits queries written by
+// agents; a known qualifier with an invalid value is still an error.
+func ParseQuery(query string) (ParsedQuery, error) {
+	return parseQuery(query, false)
+}
+
+// ParseQueryStrict is ParseQuery for queries typed by the user, such as the
+// gomor search arguments and the TUI filter: an unknown qualifier or one
+// without a value is an error, so that typos don't silently turn into
+// search terms.
+func ParseQueryStrict(query string) (ParsedQuery, error) {
+	return parseQuery(query, true)
+}
+
+func parseQuery(query string, strict bool) (ParsedQuery, error) {
+	var (
+		parsed ParsedQuery
+		text   []string
+	)
+
+	for _, tok := range splitQuery(query) {
+		if tok.quoted {
+			text = append(text, tok.value)
+			continue
+		}
+
+		name, value, ok := splitQualifier(tok.value)
+		if !ok {
+			text = append(text, tok.value)
+			continue
+		}
+		if !slices.Contains(qualifierNames, name) {
+			if !strict {
+				text = append(text, tok.value)
+				continue
+			}
+			return ParsedQuery{}, fmt.Errorf("unknown qualifier %q (supported: %s; quote the word to search for it literally)",
+				name+":", strings.Join(qualifierNames, ", "))
+		}
+		if value == "" {
+			if !strict {
+				text = append(text, tok.value)
+				continue
+			}
+			return ParsedQuery{}, fmt.Errorf("qualifier %s: is missing a value", name)
+		}
+
+		var err error
+		opts := &parsed.Options
+		switch name {
+		case QualifierTag:
+			for _, t := range strings.Split(value, ",") {
+				if t = strings.TrimSpace(t); t != "" {
+					opts.Filter.Tags = append(opts.Filter.Tags, t)
+				}
+			}
+		case QualifierSource:
+			opts.Filter.Source, err = ParseSource(value)
+		case QualifierAfter:
+			opts.Filter.CreatedAfter, err = ParseDate(value)
+		case QualifierBefore:
+			opts.Filter.CreatedBefore, err = ParseDate(value)
+		case QualifierLimit:
+			opts.TopK, err = strconv.Atoi(value)
+			if err != nil || opts.TopK < 1 {
+				err = fmt.Errorf("expected a positive integer, got %q", value)
+			}
+		case QualifierMinScore:
+			opts.MinScore, err = strconv.ParseFloat(value, 64)
+			if err != nil || opts.MinScore < 0 || opts.MinScore > 1 {
+				err = fmt.Errorf("expected a number between 0 and 1, got %q", value)
+			}
+		}
+		if err != nil {
+			return ParsedQuery{}, fmt.Errorf("qualifier %s: %w", name, err)
+		}
+	}
+
+	f := parsed.Options.Filter
+	if !f.CreatedAfter.IsZero() && !f.CreatedBefore.IsZero() && !f.CreatedAfter.Before(f.CreatedBefore) {
+		return ParsedQuery{}, fmt.Errorf("after: must be earlier than before:")
+	}
+
+	parsed.Text = strings.Join(text, " ")
+	return parsed, nil
+}
+
+type queryToken struct {
+	value  string
+	quoted bool // the whole token was a quoted phrase
+}
+
+// splitQuery splits on whitespace, keeping double-quoted sections together.
+// Quotes around a whole token mark it as text; quotes after a colon only
+// group the qualifier's value.
+func splitQuery(query string) []queryToken {
+	var (
+		tokens  []queryToken
+		current strings.Builder
+		inQuote bool
+		quoted  bool
+		started bool
+	)
+
+	flush := func() {
+		if started {
+			tokens = append(tokens, queryToken{value: current.String(), quoted: quoted})
+		}
+		current.Reset()
+		quoted, started = false, false
+	}
+
+	for _, r := range query {
+		switch {
+		case r == '"':
+			if !inQuote && !started {
+				quoted = true
+			}
+			inQuote = !inQuote
+			started = true
+		case unicode.IsSpace(r) && !inQuote:
+			flush()
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// splitQualifier reports whether tok has the form name:value with a
+// lowercase alphabetic name. URLs (scheme://...) are not qualifiers.
+func splitQualifier(tok string) (name, value string, ok bool) {
+	name, value, found := strings.Cut(tok, ":")
+	if !found || name == "" || strings.HasPrefix(value, "//") {
+		return "", "", false
+	}
+	for _, r := range name {
+		if r < 'a' || r > 'z' {
+			return "", "", false
+		}
+	}
+	return name, strings.TrimSpace(value), true
+}
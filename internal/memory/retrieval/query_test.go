@@ -0,0 +1,90 @@
+package retrieval
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	parsed, err := ParseQuery(`tag:go source:explicit after:2025-01-01 error handling tag:"code review",lint limit:5 minscore:0.4`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if parsed.Text != "error handling" {
+		t.Errorf("text: got %q", parsed.Text)
+	}
+	f := parsed.Options.Filter
+	if strings.Join(f.Tags, "|") != "go|code review|lint" {
+		t.Errorf("tags: got %v", f.Tags)
+	}
+	if f.Source != SourceExplicit {
+		t.Errorf("source: got %q", f.Source)
+	}
+	if want := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local); !f.CreatedAfter.Equal(want) {
+		t.Errorf("after: got %v want %v", f.CreatedAfter, want)
+	}
+	if parsed.Options.TopK != 5 || parsed.Options.MinScore != 0.4 {
+		t.Errorf("options: got %+v", parsed.Options)
+	}
+}
+
+func TestParseQuery_TextOnly(t *testing.T) {
+	tests := map[string]string{
+		"how do I handle errors":                       "how do I handle errors",
+		"docs at https://go.dev/doc please":            "docs at https://go.dev/doc please",
+		"meeting at 10:30":                             "meeting at 10:30",
+		`"note:this" is literal`:                       "note:this is literal",
+		"Re: the earlier question":                     "Re: the earlier question",
+		"error: nil pointer in handler":                "error: nil pointer in handler",
+		"todo: fix auth":                               "todo: fix auth",
+		"python:3.11 setup":                            "python:3.11 setup",
+		"c:/users path":                                "c:/users path",
+		"tag: errors":                                  "tag: errors",
+		`"error handling" before:2025-06-01T00:00:00Z`: "error handling",
+	}
+	for query, want := range tests {
+		parsed, err := ParseQuery(query)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", query, err)
+			continue
+		}
+		if parsed.Text != want {
+			t.Errorf("%q: got text %q want %q", query, parsed.Text, want)
+		}
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	tests := map[string]string{
+		"source:imported errors":               "unknown source",
+		"after:yesterday errors":               "invalid date",
+		"limit:0 errors":                       "positive integer",
+		"minscore:2 errors":                    "between 0 and 1",
+		"after:2025-02-01 before:2025-01-01 x": "must be earlier",
+	}
+	for query, want := range tests {
+		_, err := ParseQuery(query)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: got error %v, want it to contain %q", query, err, want)
+		}
+		if _, strictErr := ParseQueryStrict(query); strictErr == nil {
+			t.Errorf("%q: expected an error from ParseQueryStrict", query)
+		}
+	}
+}
+
+func TestParseQueryStrict_Errors(t *testing.T) {
+	tests := map[string]string{
+		"lang:go errors": `unknown qualifier "lang:"`,
+		"error: nil":     `unknown qualifier "error:"`,
+		"tag: errors":    "missing a value",
+	}
+	for query, want := range tests {
+		_, err := ParseQueryStrict(query)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: got error %v, want it to contain %q", query, err, want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package retrieval
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// recencyScorer discounts fused scores of older memories with an exponential
+// half-life. Only Weight of the score decays, so an old but highly relevant
+// memory can still outrank a new, marginal one:
+//
+//	score *= (1 - Weight) + Weight * 0.5^(age / halfLife)
+type recencyScorer struct {
+	Weight       float64
+	HalfLifeDays float64
+	Overrides    map[string]float64 // by lowercase key
+}
+
+func newRecencyScorer(config utils.MemoryConfig) recencyScorer {
+	return recencyScorer{
+		Weight:       config.RecencyWeight,
+		HalfLifeDays: config.RecencyHalfLifeDays,
+		Overrides:    lowerOverrides(config.RecencyOverrides),
+	}
+}
+
+// lowerOverrides lowercases the keys of the configured overrides, so they
+// match case-insensitively. Keys differing only in case are combined like
+// overrides matching the same memory: the slowest decay wins.
+func lowerOverrides(overrides map[string]float64) map[string]float64 {
+	lowered := make(map[string]float64, len(overrides))
+	for key, days := range overrides {
+		key = strings.ToLower(key)
+		if prev, ok := lowered[key]; ok && (prev <= 0 || (days > 0 && prev > days)) {
+			continue
+		}
+		lowered[key] = days
+	}
+	return lowered
+}
+
+func (s recencyScorer) enabled() bool {
+	return s.Weight > 0
+}
+
+// apply scales the score of every result by its recency factor as of now.
+func (s recencyScorer) apply(results []UnifiedResult, now time.Time) {
+	if !s.enabled() {
+		return
+	}
+	for i := range results {
+		results[i].Score *= s.factor(results[i].Item, now)
+	}
+}
+
+// factor returns the multiplier in [1-Weight, 1] for a memory. Memories without
+// a creation time, or covered by a never-decay override, are not discounted.
+func (s recencyScorer) factor(item MemoryItem, now time.Time) float64 {
+	if item.CreatedAt.IsZero() {
+		return 1
+	}
+	halfLife := s.halfLife(item)
+	if halfLife <= 0 {
+		return 1
+	}
+
+	ageDays := max(0, now.Sub(item.CreatedAt).Hours()/24)
+	weight := min(s.Weight, 1)
+	return (1 - weight) + weight*math.Pow(0.5, ageDays/halfLife)
+}
+
+// halfLife resolves the half-life in days for a memory. When several overrides
+// match, the slowest decay wins, and 0 (never decay) beats any other value.
+func (s recencyScorer) halfLife(item MemoryItem) float64 {
+	keys := make([]string, 0, len(item.Tags)+1)
+	keys = append(keys, "source:"+strings.ToLower(string(item.Source)))
+	for _, tag := range item.Tags {
+		keys = append(keys, "tag:"+strings.ToLower(tag))
+	}
+
+	matched := false
+	var halfLife float64
+	for _, key := range keys {
+		days, ok := s.Overrides[key]
+		if !ok {
+			continue
+		}
+		if days <= 0 {
+			return 0
+		}
+		if !matched || days > halfLife {
+			halfLife = days
+		}
+		matched = true
+	}
+	if matched {
+		return halfLife
+	}
+	return s.HalfLifeDays
+}
@@ -0,0 +1,67 @@
+package retrieval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+func TestRecencyScorer_Factor(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	s := newRecencyScorer(utils.MemoryConfig{
+		RecencyWeight:       0.5,
+		RecencyHalfLifeDays: 30,
+		RecencyOverrides: map[string]float64{
+			"tag:identity": 0, "source:extracted": 10, "tag:Project": 60,
+			"tag:Work": 10, "tag:WORK": 20, "tag:work": 15,
+			"tag:Home": 0, "tag:home": 5,
+		},
+	})
+
+	tests := []struct {
+		name string
+		item MemoryItem
+		want float64
+	}{
+		{"brand new", MemoryItem{CreatedAt: now}, 1},
+		{"one half-life", MemoryItem{CreatedAt: now.AddDate(0, 0, -30)}, 0.5 + 0.5*0.5},
+		{"unknown age", MemoryItem{}, 1},
+		{"never decays", MemoryItem{Tags: []string{"identity"}, CreatedAt: now.AddDate(-5, 0, 0)}, 1},
+		{"source override", MemoryItem{Source: SourceExtracted, CreatedAt: now.AddDate(0, 0, -10)}, 0.75},
+		// tag:project (60 days) is slower than source:extracted (10 days)
+		{"slowest override wins", MemoryItem{Source: SourceExtracted, Tags: []string{"project"}, CreatedAt: now.AddDate(0, 0, -60)}, 0.75},
+		// keys differing only in case combine the same way, whatever the map order
+		{"slowest of keys differing in case", MemoryItem{Tags: []string{"Work"}, CreatedAt: now.AddDate(0, 0, -20)}, 0.75},
+		{"never decays beats keys differing in case", MemoryItem{Tags: []string{"home"}, CreatedAt: now.AddDate(-1, 0, 0)}, 1},
+	}
+	for _, tt := range tests {
+		if got := s.factor(tt.item, now); !approxEqual(got, tt.want) {
+			t.Errorf("%s: got %f want %f", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRetriever_FuseResultsPrefersRecentMemories(t *testing.T) {
+	now := time.Now()
+	vector := []SearchResult{
+		{Item: MemoryItem{ID: "old", Text: "uses vim", CreatedAt: now.AddDate(-1, 0, 0)}, Similarity: 0.82},
+		{Item: MemoryItem{ID: "new", Text: "switched to helix", CreatedAt: now.AddDate(0, 0, -2)}, Similarity: 0.80},
+		{Item: MemoryItem{ID: "name", Text: "name is Sam", Tags: []string{"identity"}, CreatedAt: now.AddDate(-3, 0, 0)}, Similarity: 0.78},
+	}
+
+	cfg := utils.DefaultConfig().Memory
+	cfg.FusionStrategy = utils.FusionStrategyLegacy
+
+	plain := NewRetriever(nil, nil, nil, types.Model{}, types.Model{}, cfg).fuseResults(vector, nil, RetrieveOptions{TopK: cfg.MemoryTopK})
+	if got := resultIDs(plain); got != "old,new,name" {
+		t.Fatalf("without recency expected similarity order, got %s", got)
+	}
+
+	cfg.RecencyWeight = 0.3
+	recent := NewRetriever(nil, nil, nil, types.Model{}, types.Model{}, cfg).fuseResults(vector, nil, RetrieveOptions{TopK: cfg.MemoryTopK})
+	if got := resultIDs(recent); got != "new,name,old" {
+		t.Fatalf("with recency expected new,name,old, got %s", got)
+	}
+}
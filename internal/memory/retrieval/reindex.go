@@ -13,14 +13,57 @@ import (
 	"github.com/austiecodes/gomor/internal/types"
 )
 
+// maxCatchUpPasses bounds how often ReindexMemories re-scans for memories that
+// other processes saved with the old model while the reindex was running.
+const maxCatchUpPasses = 3
+
 // ReindexMemories re-calculates embeddings for all memories using the new model.
+// It holds the store's maintenance lock so that only one process reindexes at a time.
 func ReindexMemories(ctx context.Context, s *store.Store, embeddingClient client.EmbeddingClient, model types.Model) error {
+	lock, err := s.AcquireMaintenanceLock(ctx)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
 	// 1. Fetch all memories
 	memories, err := s.GetAllMemories()
 	if err != nil {
 		return fmt.Errorf("failed to fetch memories for reindexing: %w", err)
 	}
 
+	if err := ReindexMemoryItems(ctx, s, embeddingClient, model, memories); err != nil {
+		return err
+	}
+
+	// MCP servers keep saving with the old model until the new config is written,
+	// so pick up anything that was added while we were busy.
+	for pass := 0; pass < maxCatchUpPasses; pass++ {
+		memories, err = s.GetAllMemories()
+		if err != nil {
+			return fmt.Errorf("failed to fetch memories for reindexing: %w", err)
+		}
+
+		var stale []store.MemoryItem
+		for _, m := range memories {
+			if m.Provider != model.Provider || m.ModelID != model.ModelID {
+				stale = append(stale, m)
+			}
+		}
+		if len(stale) == 0 {
+			return nil
+		}
+		if err := ReindexMemoryItems(ctx, s, embeddingClient, model, stale); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReindexMemoryItems re-calculates embeddings for the given memories only.
+// Callers that rewrite many memories should hold the store's maintenance lock.
+func ReindexMemoryItems(ctx context.Context, s *store.Store, embeddingClient client.EmbeddingClient, model types.Model, memories []store.MemoryItem) error {
 	total := len(memories)
 	if total == 0 {
 		return nil
@@ -88,9 +131,9 @@ func ReindexMemories(ctx context.Context, s *store.Store, embeddingClient client
 					continue
 				}
 
-				// Try to write to DB
-				dim := embeddingClient.Dimensions(model)
-				err := s.UpdateMemoryEmbedding(job.item.ID, job.embedding, model.ModelID, dim, model.Provider)
+				// Try to write to DB; stored vectors must be normalized for dot-product search
+				embedding := NormalizeVector(job.embedding)
+				err := s.UpdateMemoryEmbedding(job.item.ID, embedding, model.ModelID, len(embedding), model.Provider)
 				if err != nil {
 					job.err = fmt.Errorf("write failed: %w", err)
 					select {
@@ -0,0 +1,129 @@
+package retrieval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/austiecodes/gomor/internal/client"
+)
+
+// rerankMaxGrade is the highest relevance grade the tool model may assign.
+const rerankMaxGrade = 3
+
+// rerankResponse is the JSON the tool model is asked to return.
+type rerankResponse struct {
+	Grades []rerankGrade `json:"grades"`
+}
+
+type rerankGrade struct {
+	ID    int `json:"id"`
+	Grade int `json:"grade"`
+}
+
+// rerankSchema constrains rerankResponse for providers with structured output.
+var rerankSchema = client.JSONSchema{
+	Name:        "memory_grades",
+	Description: "Relevance grade of each numbered memory",
+	Schema: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"grades": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"id":    map[string]any{"type": "integer"},
+						"grade": map[string]any{"type": "integer"},
+					},
+					"required":             []string{"id", "grade"},
+					"additionalProperties": false,
+				},
+			},
+		},
+		"required":             []string{"grades"},
+		"additionalProperties": false,
+	},
+}
+
+// rerank asks tool_model to grade the top candidates and reorders them by grade.
+// Only the first RerankTopN candidates are sent; the rest keep their fused order
+// after them. On timeout or any error the fused order is returned unchanged.
+// Failures are reported in opts as degradation and grades recorded when explaining.
+func (r *Retriever) rerank(ctx context.Context, query string, results []UnifiedResult, opts RetrieveOptions) []UnifiedResult {
+	if !r.reranks() || len(results) < 2 {
+		return results
+	}
+
+	n := min(len(results), r.config.RerankTopN)
+	if n < 2 {
+		return results
+	}
+	candidates := results[:n]
+
+	ctx, cancel := stageContext(ctx, r.config.RerankTimeoutMs)
+	defer cancel()
+
+	grades, err := r.gradeCandidates(ctx, query, candidates)
+	if err != nil {
+		opts.degraded.add("rerank %s, so the fused order was kept", stageError(err))
+		return results
+	}
+	opts.tracer.reranked(grades)
+
+	reranked := make([]UnifiedResult, len(results))
+	copy(reranked, results)
+	head := reranked[:n]
+	sort.SliceStable(head, func(i, j int) bool {
+		return grades[head[i].Item.ID] > grades[head[j].Item.ID]
+	})
+	return reranked
+}
+
+// reranks reports whether the tool model reorders the fused candidates.
+func (r *Retriever) reranks() bool {
+	return r.config.RerankEnabled && r.queryClient != nil
+}
+
+// gradeCandidates returns the tool model's relevance grade per memory ID.
+// Candidates the model didn't grade get -1 so they sort below graded ones.
+func (r *Retriever) gradeCandidates(ctx context.Context, query string, candidates []UnifiedResult) (map[string]int, error) {
+	var sb strings.Builder
+	for i, c := range candidates {
+		sb.WriteString(fmt.Sprintf("[%d] %s\n", i+1, strings.ReplaceAll(c.Item.Text, "\n", " ")))
+	}
+
+	prompt := fmt.Sprintf(`Rate how relevant each memory is to the user query.
+Use a grade from 0 (irrelevant) to %d (directly answers the query).
+
+User query: %s
+
+Memories:
+%s
+Respond with only JSON in this exact form (no other text):
+{"grades": [{"id": 1, "grade": 3}, {"id": 2, "grade": 0}]}`, rerankMaxGrade, query, sb.String())
+
+	response, err := client.CompleteJSON(ctx, r.queryClient, r.toolModel, prompt, rerankSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed rerankResponse
+	if err := json.Unmarshal([]byte(client.ExtractJSONObject(response)), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse rerank response: %w", err)
+	}
+
+	grades := make(map[string]int, len(candidates))
+	for _, c := range candidates {
+		grades[c.Item.ID] = -1
+	}
+	for _, g := range parsed.Grades {
+		if g.ID < 1 || g.ID > len(candidates) {
+			continue
+		}
+		grades[candidates[g.ID-1].Item.ID] = max(0, min(g.Grade, rerankMaxGrade))
+	}
+	return grades, nil
+}
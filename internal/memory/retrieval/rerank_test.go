@@ -0,0 +1,146 @@
+package retrieval
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// scriptedQueryClient answers every prompt with a fixed response after an
+// optional delay, and records the last prompt it received.
+type scriptedQueryClient struct {
+	response   string
+	delay      time.Duration
+	lastPrompt string
+}
+
+func (c *scriptedQueryClient) ChatStream(ctx context.Context, model types.Model, query string) (client.StreamResponse, error) {
+	c.lastPrompt = query
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &fakeStream{chunks: []string{c.response}}, nil
+}
+
+func (c *scriptedQueryClient) ChatStreamWithContext(ctx context.Context, model types.Model, systemContext, query string) (client.StreamResponse, error) {
+	return c.ChatStream(ctx, model, query)
+}
+
+func (c *scriptedQueryClient) ListModels(ctx context.Context) ([]string, error) {
+	return []string{"fake-model"}, nil
+}
+
+func rerankCandidates() []UnifiedResult {
+	return []UnifiedResult{
+		{Item: MemoryItem{ID: "a", Text: "likes tabs"}, Score: 0.9},
+		{Item: MemoryItem{ID: "b", Text: "works on a Go monorepo"}, Score: 0.8},
+		{Item: MemoryItem{ID: "c", Text: "prefers table-driven tests in Go"}, Score: 0.7},
+		{Item: MemoryItem{ID: "d", Text: "drinks tea"}, Score: 0.6},
+	}
+}
+
+func newRerankRetriever(qc client.QueryClient, topN int, timeout time.Duration) *Retriever {
+	cfg := utils.DefaultConfig().Memory
+	cfg.RerankEnabled = true
+	cfg.RerankTopN = topN
+	cfg.RerankTimeoutMs = int(timeout / time.Millisecond)
+	return NewRetriever(nil, nil, qc, types.Model{}, types.Model{}, cfg)
+}
+
+func resultIDs(results []UnifiedResult) string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.Item.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestRerank_ReordersByGrade(t *testing.T) {
+	qc := &scriptedQueryClient{
+		response: "```json\n{\"grades\": [{\"id\": 1, \"grade\": 0}, {\"id\": 2, \"grade\": 1}, {\"id\": 3, \"grade\": 3}]}\n```",
+	}
+	r := newRerankRetriever(qc, 3, time.Second)
+
+	got := resultIDs(r.rerank(context.Background(), "how do I write Go tests?", rerankCandidates(), RetrieveOptions{}))
+	if want := "c,b,a,d"; got != want {
+		t.Fatalf("got order %s want %s", got, want)
+	}
+
+	// Only the top N candidates are sent to the model.
+	if !strings.Contains(qc.lastPrompt, "[3] prefers table-driven tests") || strings.Contains(qc.lastPrompt, "drinks tea") {
+		t.Fatalf("prompt should contain exactly the top 3 candidates:\n%s", qc.lastPrompt)
+	}
+}
+
+func TestRank_RerankPromotesBelowTopK(t *testing.T) {
+	vector := []SearchResult{
+		{Item: MemoryItem{ID: "a", Text: "likes tabs"}, Similarity: 0.9},
+		{Item: MemoryItem{ID: "b", Text: "works on a Go monorepo"}, Similarity: 0.8},
+		{Item: MemoryItem{ID: "c", Text: "prefers table-driven tests in Go"}, Similarity: 0.7},
+		{Item: MemoryItem{ID: "d", Text: "drinks tea"}, Similarity: 0.6},
+	}
+	qc := &scriptedQueryClient{
+		response: `{"grades": [{"id": 1, "grade": 1}, {"id": 2, "grade": 0}, {"id": 3, "grade": 3}, {"id": 4, "grade": 0}]}`,
+	}
+	r := newRerankRetriever(qc, 4, time.Second)
+
+	// c is fused third, just below the top 2, but graded most relevant
+	got := resultIDs(r.rank(context.Background(), "how do I write Go tests?", vector, nil, r.resolveOptions(RetrieveOptions{TopK: 2})))
+	if want := "c,a"; got != want {
+		t.Fatalf("got %s want %s", got, want)
+	}
+	if !strings.Contains(qc.lastPrompt, "[4] drinks tea") {
+		t.Fatalf("expected all 4 candidates to be graded:\n%s", qc.lastPrompt)
+	}
+}
+
+func TestRerank_NegativeTimeoutMeansNoLimit(t *testing.T) {
+	qc := &scriptedQueryClient{
+		response: `{"grades": [{"id": 1, "grade": 0}, {"id": 2, "grade": 1}, {"id": 3, "grade": 3}]}`,
+		delay:    10 * time.Millisecond,
+	}
+	r := newRerankRetriever(qc, 3, -time.Millisecond)
+
+	degraded := &degradation{}
+	got := resultIDs(r.rerank(context.Background(), "query", rerankCandidates(), RetrieveOptions{degraded: degraded}))
+	if want := "c,b,a,d"; got != want {
+		t.Fatalf("got order %s want %s", got, want)
+	}
+	if reasons := degraded.list(); len(reasons) != 0 {
+		t.Fatalf("expected no degradation, got %v", reasons)
+	}
+}
+
+func TestRerank_TimeoutFallsBackToFusedOrder(t *testing.T) {
+	qc := &scriptedQueryClient{
+		response: `{"grades": [{"id": 4, "grade": 3}]}`,
+		delay:    time.Second,
+	}
+	r := newRerankRetriever(qc, 10, 20*time.Millisecond)
+
+	start := time.Now()
+	got := resultIDs(r.rerank(context.Background(), "query", rerankCandidates(), RetrieveOptions{}))
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("rerank should give up after its timeout, took %v", elapsed)
+	}
+	if want := "a,b,c,d"; got != want {
+		t.Fatalf("got order %s want fused order %s", got, want)
+	}
+}
+
+func TestRerank_InvalidResponseKeepsFusedOrder(t *testing.T) {
+	qc := &scriptedQueryClient{response: "I think the third one is best."}
+	r := newRerankRetriever(qc, 10, time.Second)
+
+	got := resultIDs(r.rerank(context.Background(), "query", rerankCandidates(), RetrieveOptions{}))
+	if want := "a,b,c,d"; got != want {
+		t.Fatalf("got order %s want fused order %s", got, want)
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/austiecodes/gomor/internal/client"
 	"github.com/austiecodes/gomor/internal/memory/memtypes"
@@ -23,10 +23,14 @@ type SearchResult = memtypes.SearchResult
 type MemoryFTSResult = memtypes.MemoryFTSResult
 type UnifiedResult = memtypes.UnifiedResult
 type RetrievalResponse = memtypes.RetrievalResponse
+type MemoryFilter = memtypes.MemoryFilter
+type HistorySearchResult = memtypes.HistorySearchResult
+type ModelMismatch = memtypes.ModelMismatch
 
 const (
 	SourceExplicit  = memtypes.SourceExplicit
 	SourceExtracted = memtypes.SourceExtracted
+	SourceDocument  = memtypes.SourceDocument
 )
 
 // Re-export store and vector functions for convenience
@@ -43,6 +47,9 @@ type Retriever struct {
 	embeddingModel  types.Model
 	toolModel       types.Model
 	config          utils.MemoryConfig
+	fuser           Fuser
+	recency         recencyScorer
+	clientFactory   EmbeddingClientFactory
 }
 
 // NewRetriever creates a new retriever with the given dependencies.
@@ -61,6 +68,8 @@ func NewRetriever(
 		embeddingModel:  embeddingModel,
 		toolModel:       toolModel,
 		config:          config,
+		fuser:           NewFuser(config),
+		recency:         newRecencyScorer(config),
 	}
 }
 
@@ -68,70 +77,143 @@ func NewRetriever(
 // 1. Uses tool_model to transform the query (answer + rephrase)
 // 2. Embeds transformed queries and performs vector search
 // 3. Performs FTS based on configured strategy
-// 4. Fuses and ranks results
+// 4. Fuses and ranks results, discounting older memories if configured
+// 5. Optionally reranks the top candidates with tool_model
+// Matching conversation history is returned separately as HistorySnippets.
 func (r *Retriever) Retrieve(ctx context.Context, query string) (*RetrievalResponse, error) {
-	var (
-		vectorResults []SearchResult
-		ftsResults    []MemoryFTSResult
-		vectorErr     error
-		ftsErr        error
-		wg            sync.WaitGroup
-	)
+	return r.RetrieveWithOptions(ctx, query, RetrieveOptions{})
+}
+
+// RetrieveWithOptions is Retrieve with per-call filters and limits. The filter
+// is applied inside both searches, so it narrows candidates before ranking.
+// The whole call is bounded by the latency budget: stages that fail or run out
+// of time are left out and the response is marked degraded.
+func (r *Retriever) RetrieveWithOptions(ctx context.Context, query string, opts RetrieveOptions) (*RetrievalResponse, error) {
+	opts = r.resolveOptions(opts)
+	if opts.Explain {
+		opts.tracer = &tracer{}
+	}
+	opts.degraded = &degradation{}
+	start := time.Now()
+
+	ctx, cancel := stageContext(ctx, r.config.LatencyBudgetMs)
+	defer cancel()
+
+	type vectorOutcome struct {
+		results    []SearchResult
+		mismatched []ModelMismatch
+		err        error
+	}
+	type ftsOutcome struct {
+		results []MemoryFTSResult
+		err     error
+	}
+	// Buffered so that paths finishing after the budget don't block forever
+	vectorCh := make(chan vectorOutcome, 1)
+	ftsCh := make(chan ftsOutcome, 1)
+	historyCh := make(chan []HistorySearchResult, 1)
 
 	// Run vector search path in parallel
-	wg.Add(1)
 	go func() {
-		defer wg.Done()
-		vectorResults, vectorErr = r.vectorSearch(ctx, query)
+		results, mismatched, err := r.vectorSearch(ctx, query, opts)
+		vectorCh <- vectorOutcome{results, mismatched, err}
 	}()
 
 	// Run FTS search path in parallel
-	wg.Add(1)
 	go func() {
-		defer wg.Done()
-		ftsResults, ftsErr = r.ftsSearch(ctx, query)
+		defer opts.tracer.stage("fts_search", time.Now())
+		results, err := r.ftsSearch(ctx, query, opts)
+		ftsCh <- ftsOutcome{results, err}
 	}()
 
-	wg.Wait()
+	// Search conversation history alongside; failures only drop the snippets
+	go func() {
+		defer opts.tracer.stage("history_search", time.Now())
+		historyCh <- r.historySearch(query, opts)
+	}()
 
-	// Log errors but continue if at least one path succeeded
-	if vectorErr != nil && ftsErr != nil {
-		return nil, fmt.Errorf("retrieval failed: vector: %v, fts: %v", vectorErr, ftsErr)
+	var (
+		vector                           vectorOutcome
+		fts                              ftsOutcome
+		history                          []HistorySearchResult
+		vectorDone, ftsDone, historyDone bool
+	)
+wait:
+	for !vectorDone || !ftsDone || !historyDone {
+		select {
+		case vector = <-vectorCh:
+			vectorDone = true
+		case fts = <-ftsCh:
+			ftsDone = true
+		case history = <-historyCh:
+			historyDone = true
+		case <-ctx.Done():
+			// Don't wait for clients that ignore cancellation
+			break wait
+		}
 	}
 
-	// Fuse results
-	unified := r.fuseResults(vectorResults, ftsResults)
+	for _, path := range []struct {
+		name string
+		done bool
+		err  error
+	}{
+		{"vector search", vectorDone, vector.err},
+		{"full-text search", ftsDone, fts.err},
+		{"history search", historyDone, nil},
+	} {
+		if !path.done {
+			opts.degraded.add("%s did not finish within the %dms latency budget", path.name, r.config.LatencyBudgetMs)
+		} else if path.err != nil {
+			opts.degraded.add("%s %s", path.name, stageError(path.err))
+		}
+	}
+
+	// Fail only if neither search could run for reasons other than time
+	vectorFailed := !vectorDone || vector.err != nil
+	ftsFailed := !ftsDone || fts.err != nil
+	if vectorFailed && ftsFailed && ctx.Err() == nil {
+		return nil, fmt.Errorf("retrieval failed: vector: %v, fts: %v", vector.err, fts.err)
+	}
+
+	unified := r.rank(ctx, query, vector.results, fts.results, opts)
 
+	opts.tracer.stage("total", start)
+	reasons := opts.degraded.list()
 	return &RetrievalResponse{
-		Results: unified,
-		Query:   query,
+		Results:         unified,
+		HistorySnippets: history,
+		Query:           query,
+		Mismatched:      vector.mismatched,
+		Degraded:        len(reasons) > 0,
+		DegradedReasons: reasons,
+		Explain:         opts.tracer.finish(unified),
 	}, nil
 }
 
 // vectorSearch performs vector similarity search with LLM query transformation.
-func (r *Retriever) vectorSearch(ctx context.Context, query string) ([]SearchResult, error) {
+// It also returns the memories it could not search because they were embedded
+// with another model.
+func (r *Retriever) vectorSearch(ctx context.Context, query string, opts RetrieveOptions) ([]SearchResult, []ModelMismatch, error) {
 	// Transform query using tool_model: get brief answer and rephrased query
-	transformedQueries, err := r.transformQueryForVector(ctx, query)
+	transformStart := time.Now()
+	transformCtx, cancel := stageContext(ctx, r.config.TransformTimeoutMs)
+	transformedQueries, err := r.transformQueryForVector(transformCtx, query)
+	cancel()
 	if err != nil {
 		// Fallback to original query if transformation fails
+		opts.degraded.add("query transformation %s, so only the raw query was searched", stageError(err))
 		transformedQueries = []string{query}
 	}
+	opts.tracer.stage("query_transform", transformStart)
+	opts.tracer.transformed(transformedQueries)
+	defer opts.tracer.stage("vector_search", time.Now())
 
 	// Embed all transformed queries and collect results
 	var allResults []SearchResult
+	var mismatched []ModelMismatch
 	seenIDs := make(map[string]bool)
-
-	for _, q := range transformedQueries {
-		embedding, err := r.embeddingClient.Embed(ctx, r.embeddingModel, q)
-		if err != nil {
-			continue // skip failed embeddings
-		}
-
-		results, err := r.store.SearchMemories(embedding, r.config.MemoryTopK, r.config.MinSimilarity)
-		if err != nil {
-			continue
-		}
-
+	collect := func(results []SearchResult) {
 		// Deduplicate
 		for _, res := range results {
 			if !seenIDs[res.Item.ID] {
@@ -141,95 +223,63 @@ func (r *Retriever) vectorSearch(ctx context.Context, query string) ([]SearchRes
 		}
 	}
 
-	// Re-sort by similarity and limit
-	sort.Slice(allResults, func(i, j int) bool {
-		return allResults[i].Similarity > allResults[j].Similarity
-	})
+	var embedErrs, searchErrs []error
+	for _, q := range transformedQueries {
+		embedding, err := r.embed(ctx, r.embeddingClient, r.embeddingModel, q)
+		if err != nil {
+			opts.tracer.vectorSearch(q, r.embeddingModel, nil, err)
+			embedErrs = append(embedErrs, err)
+			continue // skip failed embeddings
+		}
 
-	if len(allResults) > r.config.MemoryTopK {
-		allResults = allResults[:r.config.MemoryTopK]
+		results, stats, err := r.store.SearchMemories(embedding, r.embeddingModel, opts.TopK, r.config.MinSimilarity, opts.Filter)
+		opts.tracer.vectorSearch(q, r.embeddingModel, results, err)
+		if err != nil {
+			searchErrs = append(searchErrs, err)
+			continue
+		}
+		mismatched = stats.Mismatched // the same for every query
+		collect(results)
 	}
-
-	return allResults, nil
-}
-
-// transformQueryForVector uses tool_model to generate transformed queries for better embedding.
-// Returns: [brief answer, rephrased query for search]
-func (r *Retriever) transformQueryForVector(ctx context.Context, query string) ([]string, error) {
-	if r.queryClient == nil {
-		return []string{query}, nil
+	if len(embedErrs) > 0 {
+		opts.degraded.add("embedding %s for %d of %d queries", stageError(embedErrs[0]), len(embedErrs), len(transformedQueries))
 	}
-
-	prompt := fmt.Sprintf(`Given this user query, provide two transformations for memory retrieval:
-1. A brief 1-2 sentence answer to the query (as if you know the answer)
-2. A rephrased version optimized for semantic search
-
-User query: %s
-
-Respond in this exact format (no other text):
-ANSWER: <brief answer>
-REPHRASE: <rephrased query>`, query)
-
-	stream, err := r.queryClient.ChatStream(ctx, r.toolModel, prompt)
-	if err != nil {
-		return nil, err
+	if len(searchErrs) > 0 {
+		opts.degraded.add("vector search %s for %d of %d queries", stageError(searchErrs[0]), len(searchErrs), len(transformedQueries))
 	}
-	defer stream.Close()
 
-	var sb strings.Builder
-	for stream.Next() {
-		sb.WriteString(stream.GetChunk())
-	}
-	if err := stream.Err(); err != nil {
-		return nil, err
+	// Optionally search memories from other models with their own query embeddings
+	if len(mismatched) > 0 && r.config.MixedModelSearch && r.clientFactory != nil {
+		mismatched = r.searchOtherModels(ctx, transformedQueries, mismatched, opts, collect)
 	}
 
-	response := sb.String()
-	return parseTransformResponse(response, query), nil
-}
+	// Re-sort by similarity and limit
+	sort.Slice(allResults, func(i, j int) bool {
+		return allResults[i].Similarity > allResults[j].Similarity
+	})
 
-// parseTransformResponse extracts transformed queries from LLM response.
-func parseTransformResponse(response, originalQuery string) []string {
-	results := []string{originalQuery} // always include original
-
-	lines := strings.Split(response, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "ANSWER:") {
-			answer := strings.TrimSpace(strings.TrimPrefix(line, "ANSWER:"))
-			if answer != "" {
-				results = append(results, answer)
-			}
-		} else if strings.HasPrefix(line, "REPHRASE:") {
-			rephrase := strings.TrimSpace(strings.TrimPrefix(line, "REPHRASE:"))
-			if rephrase != "" {
-				results = append(results, rephrase)
-			}
-		}
+	if len(allResults) > opts.TopK {
+		allResults = allResults[:opts.TopK]
 	}
 
-	return results
+	return allResults, mismatched, nil
 }
 
 // ftsSearch performs FTS based on the configured strategy.
-func (r *Retriever) ftsSearch(ctx context.Context, query string) ([]MemoryFTSResult, error) {
+func (r *Retriever) ftsSearch(ctx context.Context, query string, opts RetrieveOptions) ([]MemoryFTSResult, error) {
 	// Always use auto strategy as it's the only supported mode now
-	return r.ftsSearchAuto(ctx, query)
+	return r.ftsSearchAuto(ctx, query, opts)
 }
 
 // ftsSearchDirect tokenizes the raw query and performs FTS.
-func (r *Retriever) ftsSearchDirect(query string) ([]MemoryFTSResult, error) {
-	ftsQuery := tokenizeForFTS(query)
-	if ftsQuery == "" {
-		return nil, nil
-	}
-	return r.store.SearchMemoriesFTS(ftsQuery, r.config.MemoryTopK)
+func (r *Retriever) ftsSearchDirect(query string, opts RetrieveOptions) ([]MemoryFTSResult, error) {
+	return r.ftsSearchExpression("direct", query, opts)
 }
 
 // ftsSearchSummary uses tool_model to summarize the query, then performs FTS.
-func (r *Retriever) ftsSearchSummary(ctx context.Context, query string) ([]MemoryFTSResult, error) {
+func (r *Retriever) ftsSearchSummary(ctx context.Context, query string, opts RetrieveOptions) ([]MemoryFTSResult, error) {
 	if r.queryClient == nil {
-		return r.ftsSearchDirect(query)
+		return r.ftsSearchDirect(query, opts)
 	}
 
 	prompt := fmt.Sprintf(`Summarize this query in one short sentence for text search:
@@ -237,9 +287,13 @@ Query: %s
 
 Respond with ONLY the summary, no other text.`, query)
 
+	ctx, cancel := stageContext(ctx, r.config.TransformTimeoutMs)
+	defer cancel()
+
 	stream, err := r.queryClient.ChatStream(ctx, r.toolModel, prompt)
 	if err != nil {
-		return r.ftsSearchDirect(query) // fallback
+		opts.degraded.add("query summary for full-text search %s", stageError(err))
+		return r.ftsSearchDirect(query, opts) // fallback
 	}
 	defer stream.Close()
 
@@ -247,28 +301,40 @@ Respond with ONLY the summary, no other text.`, query)
 	for stream.Next() {
 		sb.WriteString(stream.GetChunk())
 	}
+	if err := stream.Err(); err != nil {
+		opts.degraded.add("query summary for full-text search %s", stageError(err))
+	}
 
 	summary := strings.TrimSpace(sb.String())
 	if summary == "" {
-		return r.ftsSearchDirect(query)
+		return r.ftsSearchDirect(query, opts)
 	}
 
-	ftsQuery := tokenizeForFTS(summary)
+	return r.ftsSearchExpression("summary", summary, opts)
+}
+
+// ftsSearchExpression runs the FTS expression built from text, recording it
+// under strategy when explaining.
+func (r *Retriever) ftsSearchExpression(strategy, text string, opts RetrieveOptions) ([]MemoryFTSResult, error) {
+	ftsQuery := tokenizeForFTS(text)
 	if ftsQuery == "" {
+		opts.tracer.ftsSearch(strategy, text, "", nil, nil)
 		return nil, nil
 	}
-	return r.store.SearchMemoriesFTS(ftsQuery, r.config.MemoryTopK)
+	results, err := r.store.SearchMemoriesFTS(ftsQuery, opts.TopK, opts.Filter)
+	opts.tracer.ftsSearch(strategy, text, ftsQuery, results, err)
+	return results, err
 }
 
 // ftsSearchAuto tries direct first, falls back to summary if few results.
-func (r *Retriever) ftsSearchAuto(ctx context.Context, query string) ([]MemoryFTSResult, error) {
-	results, err := r.ftsSearchDirect(query)
+func (r *Retriever) ftsSearchAuto(ctx context.Context, query string, opts RetrieveOptions) ([]MemoryFTSResult, error) {
+	results, err := r.ftsSearchDirect(query, opts)
 	if err != nil {
 		return nil, err
 	}
 
 	// If we got enough results, return them
-	threshold := r.config.MemoryTopK / 2
+	threshold := opts.TopK / 2
 	if threshold < 3 {
 		threshold = 3
 	}
@@ -277,7 +343,7 @@ func (r *Retriever) ftsSearchAuto(ctx context.Context, query string) ([]MemoryFT
 	}
 
 	// Otherwise, try summary-based search
-	summaryResults, err := r.ftsSearchSummary(ctx, query)
+	summaryResults, err := r.ftsSearchSummary(ctx, query, opts)
 	if err != nil {
 		return results, nil // return what we have
 	}
@@ -297,6 +363,37 @@ func (r *Retriever) ftsSearchAuto(ctx context.Context, query string) ([]MemoryFT
 	return results, nil
 }
 
+// historySearch returns up to HistoryTopK conversation turns matching query.
+// History has no tags or source, so a filter on either excludes it entirely;
+// date filters apply to when the turn happened.
+func (r *Retriever) historySearch(query string, opts RetrieveOptions) []HistorySearchResult {
+	if r.config.HistoryTopK <= 0 || len(opts.Filter.Tags) > 0 || opts.Filter.Source != "" {
+		return nil
+	}
+
+	ftsQuery := tokenizeForFTS(query)
+	if ftsQuery == "" {
+		return nil
+	}
+	results, err := r.store.SearchHistory(ftsQuery, r.config.HistoryTopK)
+	if err != nil {
+		return nil
+	}
+
+	filtered := results[:0]
+	for _, res := range results {
+		created := res.Item.CreatedAt
+		if !opts.Filter.CreatedAfter.IsZero() && created.Before(opts.Filter.CreatedAfter) {
+			continue
+		}
+		if !opts.Filter.CreatedBefore.IsZero() && !created.Before(opts.Filter.CreatedBefore) {
+			continue
+		}
+		filtered = append(filtered, res)
+	}
+	return filtered
+}
+
 // tokenizeForFTS converts a query string to an FTS-safe query.
 func tokenizeForFTS(query string) string {
 	query = strings.TrimSpace(query)
@@ -332,58 +429,71 @@ func tokenizeForFTS(query string) string {
 	return strings.Join(tokens, " OR ")
 }
 
-// fuseResults combines vector and FTS results into a unified ranked list.
-func (r *Retriever) fuseResults(vectorResults []SearchResult, ftsResults []MemoryFTSResult) []UnifiedResult {
-	// Build a map of results by ID
-	resultMap := make(map[string]*UnifiedResult)
-
-	// Add vector results
-	for _, vr := range vectorResults {
-		resultMap[vr.Item.ID] = &UnifiedResult{
-			Item:        vr.Item,
-			VectorScore: vr.Similarity,
-			Source:      "vector",
-		}
+// rank fuses vector and FTS results, lets the tool model reorder the fused
+// candidates if reranking is enabled, and returns the top K. Reranking sees
+// more candidates than are returned, so it can promote one that fusion
+// ranked just below the cut.
+func (r *Retriever) rank(ctx context.Context, query string, vectorResults []SearchResult, ftsResults []MemoryFTSResult, opts RetrieveOptions) []UnifiedResult {
+	fuseStart := time.Now()
+	results := r.fuseResults(vectorResults, ftsResults, opts)
+	opts.tracer.stage("fusion", fuseStart)
+
+	rerankStart := time.Now()
+	results = r.rerank(ctx, query, results, opts)
+	opts.tracer.stage("rerank", rerankStart)
+
+	if len(results) > opts.TopK {
+		results = results[:opts.TopK]
 	}
+	return results
+}
+
+// fuseResults combines vector and FTS results into a unified ranked list
+// using the configured fusion strategy. It returns the top K, or the top
+// RerankTopN when more are to be reranked.
+func (r *Retriever) fuseResults(vectorResults []SearchResult, ftsResults []MemoryFTSResult, opts RetrieveOptions) []UnifiedResult {
+	results := r.fuser.Fuse(vectorResults, ftsResults)
+	opts.tracer.fused(r.fuser, vectorResults, ftsResults, results)
 
-	// Add/merge FTS results
-	for _, fr := range ftsResults {
-		if existing, ok := resultMap[fr.Item.ID]; ok {
-			// Memory found in both - mark as "both"
-			existing.Source = "both"
-			existing.FTSRank = fr.Rank
-			existing.Snippet = fr.Snippet
-		} else {
-			resultMap[fr.Item.ID] = &UnifiedResult{
-				Item:    fr.Item,
-				FTSRank: fr.Rank,
-				Snippet: fr.Snippet,
-				Source:  "fts",
+	// Discount older memories before ranking
+	r.recency.apply(results, time.Now())
+
+	// Drop results below the requested minimum score
+	if opts.MinScore > 0 {
+		kept := results[:0]
+		for _, res := range results {
+			if res.Score >= opts.MinScore {
+				kept = append(kept, res)
 			}
 		}
+		results = kept
 	}
 
-	// Calculate unified scores and convert to slice
-	var results []UnifiedResult
-	for _, ur := range resultMap {
-		ur.Score = calculateUnifiedScore(ur)
-		results = append(results, *ur)
-	}
-
-	// Sort by unified score descending
-	sort.Slice(results, func(i, j int) bool {
+	// Sort by unified score descending; stable so ties keep fusion order
+	sort.SliceStable(results, func(i, j int) bool {
 		return results[i].Score > results[j].Score
 	})
 
-	// Limit to top K
-	if len(results) > r.config.MemoryTopK {
-		results = results[:r.config.MemoryTopK]
+	// Keep enough candidates for reranking to choose the top K from
+	n := opts.TopK
+	if r.reranks() {
+		n = max(n, r.config.RerankTopN)
+	}
+
+	// Optionally trade some relevance for diversity among the candidates
+	if r.config.MMREnabled {
+		return mmrRerank(results, r.config.MMRLambda, n)
+	}
+
+	// Limit to the candidates
+	if len(results) > n {
+		results = results[:n]
 	}
 
 	return results
 }
 
-// calculateUnifiedScore computes a normalized score for ranking.
+// calculateUnifiedScore computes the legacy normalized score for ranking.
 // Memories found in both vector and FTS get a boost.
 func calculateUnifiedScore(ur *UnifiedResult) float64 {
 	var score float64
@@ -421,23 +531,3 @@ func calculateUnifiedScore(ur *UnifiedResult) float64 {
 
 	return score
 }
-
-// FormatAsText formats the retrieval results as readable text.
-func FormatAsText(resp *RetrievalResponse) string {
-	if resp == nil || len(resp.Results) == 0 {
-		return "No memories found."
-	}
-
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("Found %d memories:\n\n", len(resp.Results)))
-
-	for i, r := range resp.Results {
-		sb.WriteString(fmt.Sprintf("%d. [%.2f] %s\n", i+1, r.Score, r.Item.Text))
-		if len(r.Item.Tags) > 0 {
-			sb.WriteString(fmt.Sprintf("   Tags: %s\n", strings.Join(r.Item.Tags, ", ")))
-		}
-		sb.WriteString(fmt.Sprintf("   Source: %s\n", r.Source))
-	}
-
-	return sb.String()
-}
@@ -292,7 +292,7 @@ func TestRetriever_RealClients_Debug(t *testing.T) {
 
 	// Step 2: Vector search
 	fmt.Println("========== STEP 2: VECTOR SEARCH ==========")
-	vectorResults, err := retriever.vectorSearch(ctx, query)
+	vectorResults, _, err := retriever.vectorSearch(ctx, query, retriever.resolveOptions(RetrieveOptions{}))
 	if err != nil {
 		fmt.Printf("Vector search error: %v\n", err)
 	} else {
@@ -305,7 +305,7 @@ func TestRetriever_RealClients_Debug(t *testing.T) {
 
 	// Step 3: FTS search
 	fmt.Println("========== STEP 3: FTS SEARCH ==========")
-	ftsResults, err := retriever.ftsSearch(ctx, query)
+	ftsResults, err := retriever.ftsSearch(ctx, query, retriever.resolveOptions(RetrieveOptions{}))
 	if err != nil {
 		fmt.Printf("FTS search error: %v\n", err)
 	} else {
@@ -0,0 +1,170 @@
+package retrieval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/austiecodes/gomor/internal/client"
+)
+
+// transformSchema is the structured response requested from tool_model when
+// its provider supports structured output.
+var transformSchema = client.JSONSchema{
+	Name:        "query_transformations",
+	Description: "Hypothetical answers and rephrasings of a user query, used to search memories",
+	Schema: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"answers":     map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"rephrasings": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		},
+		"required":             []string{"answers", "rephrasings"},
+		"additionalProperties": false,
+	},
+}
+
+// transformResponse is the JSON form of a query transformation.
+type transformResponse struct {
+	Answers     []string `json:"answers"`
+	Rephrasings []string `json:"rephrasings"`
+}
+
+// transformQueryForVector uses tool_model to write hypothetical answers and
+// rephrasings of query, which are embedded alongside it. The original query
+// always comes first. Providers with structured output return them as typed
+// JSON; otherwise, or if that fails, the model is asked for ANSWER:/REPHRASE: lines.
+func (r *Retriever) transformQueryForVector(ctx context.Context, query string) ([]string, error) {
+	answers, rephrasings := r.transformCounts()
+	if r.queryClient == nil || answers+rephrasings == 0 {
+		return []string{query}, nil
+	}
+
+	if sc, ok := r.queryClient.(client.StructuredQueryClient); ok {
+		prompt := transformPrompt(query, answers, rephrasings) +
+			`Respond with JSON holding an "answers" array and a "rephrasings" array of strings.`
+		if response, err := sc.ChatJSON(ctx, r.toolModel, prompt, transformSchema); err == nil {
+			var parsed transformResponse
+			if err := json.Unmarshal([]byte(client.ExtractJSONObject(response)), &parsed); err == nil {
+				return collectTransformations(query, parsed, answers, rephrasings), nil
+			}
+		}
+		// Some models and OpenAI-compatible servers reject schemas; use the line format
+	}
+
+	prompt := transformPrompt(query, answers, rephrasings) + `Respond with one line per item in this exact format (no other text):
+ANSWER: <brief answer>
+REPHRASE: <rephrased query>`
+
+	stream, err := r.queryClient.ChatStream(ctx, r.toolModel, prompt)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.ReadStream(stream)
+	if err != nil {
+		return nil, err
+	}
+	return parseTransformResponse(response, query, answers, rephrasings), nil
+}
+
+// transformCounts returns how many answers and rephrasings to ask for.
+// 0 means the default of one; negative counts turn that kind off.
+func (r *Retriever) transformCounts() (answers, rephrasings int) {
+	count := func(n int) int {
+		if n == 0 {
+			return 1
+		}
+		return max(n, 0)
+	}
+	return count(r.config.TransformAnswers), count(r.config.TransformRephrasings)
+}
+
+func transformPrompt(query string, answers, rephrasings int) string {
+	var sb strings.Builder
+	sb.WriteString("Given this user query, provide transformations for memory retrieval:\n")
+	if answers > 0 {
+		sb.WriteString(fmt.Sprintf("- %d brief 1-2 sentence answer(s) to the query (as if you know the answer)\n", answers))
+	}
+	if rephrasings > 0 {
+		sb.WriteString(fmt.Sprintf("- %d rephrased version(s) optimized for semantic search\n", rephrasings))
+	}
+	sb.WriteString(fmt.Sprintf("\nUser query: %s\n\n", query))
+	return sb.String()
+}
+
+// parseTransformResponse extracts transformed queries from a line-format
+// response. Labels may be decorated (**Answer:**, - REPHRASE:) and an item may
+// continue over several lines until a blank line or the next label; lines
+// before the first label are ignored.
+func parseTransformResponse(response, originalQuery string, answers, rephrasings int) []string {
+	var (
+		parsed  transformResponse
+		current *[]string
+	)
+
+	for _, line := range strings.Split(response, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			current = nil
+			continue
+		}
+
+		label, rest, ok := transformLabel(line)
+		switch {
+		case ok && label == "answer":
+			parsed.Answers = append(parsed.Answers, rest)
+			current = &parsed.Answers
+		case ok && label == "rephrase":
+			parsed.Rephrasings = append(parsed.Rephrasings, rest)
+			current = &parsed.Rephrasings
+		case current != nil:
+			last := len(*current) - 1
+			(*current)[last] = strings.TrimSpace((*current)[last] + " " + line)
+		}
+	}
+
+	return collectTransformations(originalQuery, parsed, answers, rephrasings)
+}
+
+// transformLabel splits "ANSWER: text" into its lowercased label and text,
+// ignoring list markers and markdown emphasis around the label.
+func transformLabel(line string) (label, rest string, ok bool) {
+	line = strings.TrimLeft(line, "-*# ")
+	name, rest, found := strings.Cut(line, ":")
+	if !found {
+		return "", "", false
+	}
+	name = strings.ToLower(strings.Trim(name, "* "))
+	if name != "answer" && name != "rephrase" {
+		return "", "", false
+	}
+	return name, strings.TrimSpace(strings.TrimLeft(rest, "* ")), true
+}
+
+// collectTransformations returns the original query followed by up to answers
+// answers and rephrasings rephrasings, skipping empty and repeated entries.
+func collectTransformations(originalQuery string, parsed transformResponse, answers, rephrasings int) []string {
+	results := []string{originalQuery} // always include original
+	seen := map[string]bool{strings.ToLower(originalQuery): true}
+
+	add := func(items []string, limit int) {
+		added := 0
+		for _, item := range items {
+			item = strings.TrimSpace(item)
+			if added == limit {
+				return
+			}
+			if item == "" || seen[strings.ToLower(item)] {
+				continue
+			}
+			seen[strings.ToLower(item)] = true
+			results = append(results, item)
+			added++
+		}
+	}
+	add(parsed.Answers, answers)
+	add(parsed.Rephrasings, rephrasings)
+
+	return results
+}
@@ -0,0 +1,100 @@
+package retrieval
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/austiecodes/gomor/internal/client"
+	"github.com/austiecodes/gomor/internal/types"
+	"github.com/austiecodes/gomor/internal/utils"
+)
+
+// structuredQueryClient returns a fixed JSON response, or fails, for ChatJSON
+// and falls back to the scripted line response for ChatStream.
+type structuredQueryClient struct {
+	scriptedQueryClient
+	json       string
+	jsonErr    error
+	lastSchema client.JSONSchema
+}
+
+func (c *structuredQueryClient) ChatJSON(ctx context.Context, model types.Model, query string, schema client.JSONSchema) (string, error) {
+	c.lastPrompt, c.lastSchema = query, schema
+	return c.json, c.jsonErr
+}
+
+func newTransformRetriever(qc client.QueryClient, answers, rephrasings int) *Retriever {
+	cfg := utils.DefaultConfig().Memory
+	cfg.TransformAnswers = answers
+	cfg.TransformRephrasings = rephrasings
+	return NewRetriever(nil, nil, qc, types.Model{}, types.Model{}, cfg)
+}
+
+func TestTransformQueryForVector_Structured(t *testing.T) {
+	qc := &structuredQueryClient{json: `{"answers": ["Uses Neovim", "Uses Helix", "Uses Emacs"], "rephrasings": ["preferred code editor", "  ", "preferred code editor"]}`}
+	r := newTransformRetriever(qc, 2, 2)
+
+	got, err := r.transformQueryForVector(context.Background(), "which editor?")
+	if err != nil {
+		t.Fatalf("transform: %v", err)
+	}
+	// Counts cap each kind; empty and repeated entries are dropped
+	if want := "which editor?|Uses Neovim|Uses Helix|preferred code editor"; strings.Join(got, "|") != want {
+		t.Fatalf("got %q want %q", strings.Join(got, "|"), want)
+	}
+	if qc.lastSchema.Name != transformSchema.Name || !strings.Contains(qc.lastPrompt, "2 brief") {
+		t.Fatalf("expected a structured request for 2 answers, got schema %q prompt %q", qc.lastSchema.Name, qc.lastPrompt)
+	}
+}
+
+func TestTransformQueryForVector_FallsBackToLines(t *testing.T) {
+	qc := &structuredQueryClient{jsonErr: errors.New("response_format not supported")}
+	qc.response = "ANSWER: Uses Neovim\nREPHRASE: preferred code editor"
+	r := newTransformRetriever(qc, 1, 1)
+
+	got, err := r.transformQueryForVector(context.Background(), "which editor?")
+	if err != nil {
+		t.Fatalf("transform: %v", err)
+	}
+	if want := "which editor?|Uses Neovim|preferred code editor"; strings.Join(got, "|") != want {
+		t.Fatalf("got %q want %q", strings.Join(got, "|"), want)
+	}
+	if !strings.Contains(qc.lastPrompt, "ANSWER: <brief answer>") {
+		t.Fatalf("fallback should ask for the line format, got %q", qc.lastPrompt)
+	}
+}
+
+func TestTransformQueryForVector_Disabled(t *testing.T) {
+	qc := &structuredQueryClient{json: `{"answers": ["x"], "rephrasings": ["y"]}`}
+	r := newTransformRetriever(qc, -1, -1)
+
+	got, err := r.transformQueryForVector(context.Background(), "which editor?")
+	if err != nil {
+		t.Fatalf("transform: %v", err)
+	}
+	if len(got) != 1 || qc.lastPrompt != "" {
+		t.Fatalf("negative counts should skip the tool model, got %q", got)
+	}
+}
+
+func TestParseTransformResponse(t *testing.T) {
+	response := `Sure! Here are the transformations:
+
+**ANSWER:** The user edits code in Neovim
+with a custom Lua config.
+
+- Rephrase: preferred text editor for programming
+Answer: second answer beyond the limit`
+
+	got := parseTransformResponse(response, "which editor?", 1, 2)
+	want := []string{
+		"which editor?",
+		"The user edits code in Neovim with a custom Lua config.",
+		"preferred text editor for programming",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("got %q want %q", got, want)
+	}
+}
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Actions recorded in the audit log.
+const (
+	AuditDelete = "delete"
+	AuditForget = "forget"
+)
+
+// AuditEntry is a memory deleted on request, as it was before the deletion.
+type AuditEntry struct {
+	ID        int64
+	Action    string // AuditDelete or AuditForget
+	MemoryID  string
+	Text      string
+	Tags      []string
+	Source    MemorySource
+	Reason    string // what asked for the deletion, e.g. the forget query
+	CreatedAt time.Time
+}
+
+// DeleteMemoriesAudited deletes the memories in ids in one transaction,
+// recording each in the audit log with action and reason first. IDs that
+// don't exist are skipped; the IDs actually deleted are returned.
+func (s *Store) DeleteMemoriesAudited(ids []string, action, reason string) ([]string, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin deletion: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+	var deleted []string
+	for _, id := range ids {
+		if _, err := tx.Exec(insertAuditEntrySQL, action, reason, now, id); err != nil {
+			return nil, fmt.Errorf("failed to record deletion: %w", err)
+		}
+		res, err := tx.Exec(deleteMemorySQL, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete memory: %w", err)
+		}
+		if n, err := res.RowsAffected(); err == nil && n > 0 {
+			deleted = append(deleted, id)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit deletion: %w", err)
+	}
+	return deleted, nil
+}
+
+// GetAuditLog returns the latest limit entries of the audit log, newest first.
+func (s *Store) GetAuditLog(limit int) ([]AuditEntry, error) {
+	rows, err := s.db.Query(selectAuditLogSQL, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var (
+			e             AuditEntry
+			tagsJSON      *string
+			createdAtUnix int64
+		)
+		if err := rows.Scan(&e.ID, &e.Action, &e.MemoryID, &e.Text, &tagsJSON, &e.Source, &e.Reason, &createdAtUnix); err != nil {
+			return nil, fmt.Errorf("failed to scan audit log row: %w", err)
+		}
+		if tagsJSON != nil {
+			_ = json.Unmarshal([]byte(*tagsJSON), &e.Tags) // ignore malformed tags
+		}
+		e.CreatedAt = time.Unix(createdAtUnix, 0)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
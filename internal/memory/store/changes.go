@@ -0,0 +1,62 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// Kinds of MemoryChange.
+const (
+	ChangeInsert = "insert"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// MemoryChange is one entry of the change log that triggers keep for the
+// memories table, whichever process made the change.
+type MemoryChange struct {
+	Seq       int64 // increases with every change
+	MemoryID  string
+	Op        string // ChangeInsert, ChangeUpdate or ChangeDelete
+	CreatedAt time.Time
+}
+
+// LatestMemoryChange returns the sequence number of the most recent change,
+// or 0 if none is logged. Pass it to GetMemoryChanges to see only later changes.
+func (s *Store) LatestMemoryChange() (int64, error) {
+	var seq int64
+	if err := s.db.QueryRow(selectLatestMemoryChangeSQL).Scan(&seq); err != nil {
+		return 0, fmt.Errorf("failed to query memory changes: %w", err)
+	}
+	return seq, nil
+}
+
+// GetMemoryChanges returns up to limit changes made after the change
+// numbered after, oldest first.
+func (s *Store) GetMemoryChanges(after int64, limit int) ([]MemoryChange, error) {
+	rows, err := s.db.Query(selectMemoryChangesSQL, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query memory changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []MemoryChange
+	for rows.Next() {
+		var c MemoryChange
+		var createdAtUnix int64
+		if err := rows.Scan(&c.Seq, &c.MemoryID, &c.Op, &createdAtUnix); err != nil {
+			return nil, fmt.Errorf("failed to scan memory change row: %w", err)
+		}
+		c.CreatedAt = time.Unix(createdAtUnix, 0)
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// PruneMemoryChanges deletes changes logged before cutoff.
+func (s *Store) PruneMemoryChanges(cutoff time.Time) error {
+	if _, err := s.db.Exec(pruneMemoryChangesSQL, cutoff.Unix()); err != nil {
+		return fmt.Errorf("failed to prune memory changes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetDocumentChunks returns the chunks stored for an ingested document, in order.
+func (s *Store) GetDocumentChunks(documentID string) ([]MemoryItem, error) {
+	rows, err := s.db.Query(selectDocumentChunksSQL, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query document chunks: %w", err)
+	}
+	return scanMemories(rows)
+}
+
+// ReplaceDocumentChunks makes chunks the stored chunks of documentID, in one
+// transaction. Chunks whose ID is already stored for the document are kept
+// as they are, apart from their index, source path and tags; chunks without
+// an ID are inserted; stored chunks missing from chunks are deleted. The
+// caller reuses IDs for chunks whose content hash didn't change, so only
+// changed chunks need new embeddings.
+func (s *Store) ReplaceDocumentChunks(documentID string, chunks []MemoryItem) error {
+	existing, err := s.GetDocumentChunks(documentID)
+	if err != nil {
+		return err
+	}
+	stored := make(map[string]bool, len(existing))
+	for _, c := range existing {
+		stored[c.ID] = true
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin document update: %w", err)
+	}
+	defer tx.Rollback()
+
+	keep := make(map[string]bool, len(chunks))
+	now := time.Now()
+	for i := range chunks {
+		chunk := &chunks[i]
+		chunk.DocumentID = documentID
+
+		if chunk.ID != "" && stored[chunk.ID] {
+			keep[chunk.ID] = true
+			tagsJSON, err := json.Marshal(chunk.Tags)
+			if err != nil {
+				return fmt.Errorf("failed to marshal tags: %w", err)
+			}
+			if _, err := tx.Exec(updateDocumentChunkSQL, chunk.ChunkIndex, chunk.SourcePath, string(tagsJSON), chunk.ID, documentID); err != nil {
+				return fmt.Errorf("failed to update document chunk: %w", err)
+			}
+			continue
+		}
+
+		if chunk.ID == "" {
+			chunk.ID = uuid.New().String()
+		}
+		if chunk.CreatedAt.IsZero() {
+			chunk.CreatedAt = now
+		}
+		if err := insertMemory(tx, chunk); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range existing {
+		if keep[c.ID] {
+			continue
+		}
+		if _, err := tx.Exec(deleteDocumentChunkSQL, c.ID, documentID); err != nil {
+			return fmt.Errorf("failed to delete document chunk: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit document update: %w", err)
+	}
+	return nil
+}
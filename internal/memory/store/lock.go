@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// lockPollInterval is how often a blocked lock acquisition retries.
+const lockPollInterval = 100 * time.Millisecond
+
+// errLockBusy is returned by tryLockFile when another process holds the lock.
+var errLockBusy = errors.New("lock is held by another process")
+
+// MaintenanceLock is a cross-process advisory lock held during long maintenance
+// jobs such as reindexing, so that concurrent gomor processes don't run them at once.
+type MaintenanceLock struct {
+	f *os.File
+}
+
+// AcquireMaintenanceLock blocks until the maintenance lock for this store is
+// acquired or ctx is done. Stores without a backing file get a no-op lock.
+func (s *Store) AcquireMaintenanceLock(ctx context.Context) (*MaintenanceLock, error) {
+	if s.path == "" {
+		return &MaintenanceLock{}, nil
+	}
+
+	f, err := os.OpenFile(s.path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	for {
+		err := tryLockFile(f)
+		if err == nil {
+			return &MaintenanceLock{f: f}, nil
+		}
+		if !errors.Is(err, errLockBusy) {
+			f.Close()
+			return nil, fmt.Errorf("failed to acquire maintenance lock: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			f.Close()
+			return nil, fmt.Errorf("another maintenance job is running: %w", ctx.Err())
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// Release releases the lock. It is safe to call on a nil or no-op lock.
+func (l *MaintenanceLock) Release() error {
+	if l == nil || l.f == nil {
+		return nil
+	}
+	err := unlockFile(l.f)
+	if cerr := l.f.Close(); err == nil {
+		err = cerr
+	}
+	l.f = nil
+	return err
+}
@@ -0,0 +1,62 @@
+package store
+
+import "fmt"
+
+// FTSStatus describes how well the memories FTS index matches the memories table.
+type FTSStatus struct {
+	Memories int // rows in memories
+	Indexed  int // documents in memories_fts
+	Missing  int // memories without an FTS entry
+	Orphaned int // FTS entries without a memory
+	Corrupt  bool
+}
+
+// InSync reports whether the FTS index matches the memories table.
+func (st FTSStatus) InSync() bool {
+	return !st.Corrupt && st.Missing == 0 && st.Orphaned == 0
+}
+
+// IntegrityCheck runs PRAGMA integrity_check and returns the reported problems.
+// An empty result means the database is healthy.
+func (s *Store) IntegrityCheck() ([]string, error) {
+	rows, err := s.db.Query(integrityCheckSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run integrity check: %w", err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, fmt.Errorf("failed to scan integrity check row: %w", err)
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	return problems, rows.Err()
+}
+
+// CheckMemoriesFTS compares the memories FTS index with the memories table.
+func (s *Store) CheckMemoriesFTS() (FTSStatus, error) {
+	var st FTSStatus
+	err := s.db.QueryRow(countMemoriesFTSDriftSQL).Scan(&st.Memories, &st.Indexed, &st.Missing, &st.Orphaned)
+	if err != nil {
+		return st, fmt.Errorf("failed to compare memories with FTS index: %w", err)
+	}
+
+	// The FTS5 integrity check also catches stale tokens that the row counts can't see.
+	if _, err := s.db.Exec(checkMemoriesFTSSQL); err != nil {
+		st.Corrupt = true
+	}
+	return st, nil
+}
+
+// RebuildMemoriesFTS rebuilds the memories FTS index from the memories table.
+func (s *Store) RebuildMemoriesFTS() error {
+	if _, err := s.db.Exec(rebuildMemoriesFTSSQL); err != nil {
+		return fmt.Errorf("failed to rebuild memories FTS index: %w", err)
+	}
+	return nil
+}
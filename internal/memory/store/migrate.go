@@ -0,0 +1,59 @@
+package store
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+)
+
+// migrationsDir is where migrationsFS keeps the migration files.
+const migrationsDir = "sql/migrations"
+
+// migrate brings the schema up to date. The schema file creates the original
+// tables; each file in sql/migrations then changes them once, in file name
+// order. PRAGMA user_version records how many migrations a database has had.
+//
+// Migrations run in one immediate transaction, so gomor processes opening the
+// same database at once apply each migration exactly once.
+func (s *Store) migrate() error {
+	entries, err := fs.ReadDir(migrationsFS, migrationsDir)
+	if err != nil {
+		return fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin migration: %w", err)
+	}
+	defer tx.Rollback()
+
+	var version int
+	if err := tx.QueryRow(selectUserVersionSQL).Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version > len(entries) {
+		return fmt.Errorf("memory database schema version %d is newer than this gomor supports (%d); upgrade gomor", version, len(entries))
+	}
+	if version == len(entries) {
+		return nil
+	}
+
+	for _, entry := range entries[version:] {
+		migration, err := fs.ReadFile(migrationsFS, path.Join(migrationsDir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+		if _, err := tx.Exec(string(migration)); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", entry.Name(), err)
+		}
+	}
+
+	// PRAGMA values can't be bound as parameters
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", len(entries))); err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migrations: %w", err)
+	}
+	return nil
+}
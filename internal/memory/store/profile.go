@@ -0,0 +1,105 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrProfileChanged is returned by SaveProfile when another version of the
+// profile was saved since the one it revises.
+var ErrProfileChanged = errors.New("profile changed since it was read")
+
+// SetMemoryPinned pins or unpins a memory. Pinned memories always go into
+// the profile document.
+func (s *Store) SetMemoryPinned(id string, pinned bool) error {
+	res, err := s.db.Exec(updateMemoryPinnedSQL, pinned, id)
+	if err != nil {
+		return fmt.Errorf("failed to update memory pin: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("memory %s not found", id)
+	}
+	return nil
+}
+
+// SetMemoryImportance sets the importance of a memory, from 0 to 1.
+// 0 clears it.
+func (s *Store) SetMemoryImportance(id string, importance float64) error {
+	if importance < 0 || importance > 1 {
+		return fmt.Errorf("importance must be between 0 and 1, got %g", importance)
+	}
+	var value any
+	if importance > 0 {
+		value = importance
+	}
+	res, err := s.db.Exec(updateMemoryImportanceSQL, value, id)
+	if err != nil {
+		return fmt.Errorf("failed to update memory importance: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("memory %s not found", id)
+	}
+	return nil
+}
+
+// GetProfile returns the latest version of the profile document, or nil if
+// none has been written yet.
+func (s *Store) GetProfile() (*Profile, error) {
+	var (
+		profile       Profile
+		memoryIDs     string
+		memoryHashes  string
+		createdAtUnix int64
+	)
+	err := s.db.QueryRow(selectLatestProfileSQL).Scan(&profile.Version, &profile.Content, &memoryIDs, &memoryHashes, &createdAtUnix)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query profile: %w", err)
+	}
+	if err := json.Unmarshal([]byte(memoryIDs), &profile.MemoryIDs); err != nil {
+		profile.MemoryIDs = nil // ignore malformed IDs
+	}
+	if err := json.Unmarshal([]byte(memoryHashes), &profile.MemoryHashes); err != nil {
+		profile.MemoryHashes = nil // ignore malformed hashes; the profile is then stale
+	}
+	profile.CreatedAt = time.Unix(createdAtUnix, 0)
+	return &profile, nil
+}
+
+// SaveProfile stores content as a new version of the profile document,
+// written from the memories in memoryIDs, and returns it. memoryHashes holds
+// a hash of what each of those memories said, by ID. revises is the version
+// the new one was written from, or 0 for the first; if it is no longer the
+// latest, nothing is saved and ErrProfileChanged is returned, so that
+// concurrent writers, in this process or another, don't both save.
+func (s *Store) SaveProfile(content string, memoryIDs []string, memoryHashes map[string]string, revises int) (*Profile, error) {
+	if memoryIDs == nil {
+		memoryIDs = []string{}
+	}
+	if memoryHashes == nil {
+		memoryHashes = map[string]string{}
+	}
+	idsJSON, err := json.Marshal(memoryIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal profile memory IDs: %w", err)
+	}
+	hashesJSON, err := json.Marshal(memoryHashes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal profile memory hashes: %w", err)
+	}
+
+	profile := &Profile{Content: content, MemoryIDs: memoryIDs, MemoryHashes: memoryHashes, CreatedAt: time.Now()}
+	err = s.db.QueryRow(insertProfileVersionSQL, content, string(idsJSON), string(hashesJSON), profile.CreatedAt.Unix(), revises).Scan(&profile.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrProfileChanged
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save profile: %w", err)
+	}
+	return profile, nil
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrMemoryChanged is returned by ConsolidateMemories when a source is no
+// longer as it was read.
+var ErrMemoryChanged = errors.New("memory changed since it was read")
+
+// ConsolidateMemories saves merged in place of sources, in one transaction:
+// merged is inserted, linked to each source, and the sources are archived so
+// retrieval no longer returns them. If a source is no longer active or its
+// text changed since it was read, nothing is saved and ErrMemoryChanged is
+// returned.
+func (s *Store) ConsolidateMemories(merged *MemoryItem, sources []MemoryItem) error {
+	if merged.ID == "" {
+		merged.ID = uuid.New().String()
+	}
+	if merged.CreatedAt.IsZero() {
+		merged.CreatedAt = time.Now()
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin consolidation: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertMemory(tx, merged); err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	for _, src := range sources {
+		res, err := tx.Exec(archiveConsolidatedMemorySQL, src.ID, src.Text)
+		if err != nil {
+			return fmt.Errorf("failed to archive memory: %w", err)
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return fmt.Errorf("memory %s: %w", src.ID, ErrMemoryChanged)
+		}
+		if _, err := tx.Exec(insertMemorySourceSQL, merged.ID, src.ID, now); err != nil {
+			return fmt.Errorf("failed to link memory source: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit consolidation: %w", err)
+	}
+	return nil
+}
+
+// GetMemorySources returns the memories that were merged into id, oldest first.
+func (s *Store) GetMemorySources(id string) ([]MemoryItem, error) {
+	rows, err := s.db.Query(selectMemorySourcesSQL, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query memory sources: %w", err)
+	}
+	return scanMemories(rows)
+}
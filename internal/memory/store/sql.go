@@ -1,18 +1,25 @@
 package store
 
-import _ "embed"
+import "embed"
 
 // Schema SQL
 //
 //go:embed sql/schema/schema.sql
 var schemaSQL string
 
+// Migrations applied on top of the schema, in file name order
+//
+//go:embed sql/migrations/*.sql
+var migrationsFS embed.FS
+
 // Query SQL - each file contains a single query
 var (
 	//go:embed sql/queries/insert_memory.sql
 	insertMemorySQL string
 	//go:embed sql/queries/select_all_memories.sql
 	selectAllMemoriesSQL string
+	//go:embed sql/queries/select_filtered_memories.sql
+	selectFilteredMemoriesSQL string
 	//go:embed sql/queries/delete_memory.sql
 	deleteMemorySQL string
 	//go:embed sql/queries/update_memory_embedding.sql
@@ -27,6 +34,60 @@ var (
 	searchHistoryFTSSQL string
 	//go:embed sql/queries/select_recent_history.sql
 	selectRecentHistorySQL string
+	//go:embed sql/queries/select_session_history.sql
+	selectSessionHistorySQL string
 	//go:embed sql/queries/clear_history.sql
 	clearHistorySQL string
+	//go:embed sql/queries/integrity_check.sql
+	integrityCheckSQL string
+	//go:embed sql/queries/check_memories_fts.sql
+	checkMemoriesFTSSQL string
+	//go:embed sql/queries/count_memories_fts_drift.sql
+	countMemoriesFTSDriftSQL string
+	//go:embed sql/queries/rebuild_memories_fts.sql
+	rebuildMemoriesFTSSQL string
+	//go:embed sql/queries/select_user_version.sql
+	selectUserVersionSQL string
+	//go:embed sql/queries/select_document_chunks.sql
+	selectDocumentChunksSQL string
+	//go:embed sql/queries/update_document_chunk.sql
+	updateDocumentChunkSQL string
+	//go:embed sql/queries/delete_document_chunk.sql
+	deleteDocumentChunkSQL string
+	//go:embed sql/queries/update_memory_status.sql
+	updateMemoryStatusSQL string
+	//go:embed sql/queries/archive_consolidated_memory.sql
+	archiveConsolidatedMemorySQL string
+	//go:embed sql/queries/insert_memory_source.sql
+	insertMemorySourceSQL string
+	//go:embed sql/queries/select_memory_sources.sql
+	selectMemorySourcesSQL string
+	//go:embed sql/queries/update_memory_pinned.sql
+	updateMemoryPinnedSQL string
+	//go:embed sql/queries/update_memory_importance.sql
+	updateMemoryImportanceSQL string
+	//go:embed sql/queries/select_latest_profile.sql
+	selectLatestProfileSQL string
+	//go:embed sql/queries/insert_profile_version.sql
+	insertProfileVersionSQL string
+	//go:embed sql/queries/select_memory.sql
+	selectMemorySQL string
+	//go:embed sql/queries/select_memory_changes.sql
+	selectMemoryChangesSQL string
+	//go:embed sql/queries/select_latest_memory_change.sql
+	selectLatestMemoryChangeSQL string
+	//go:embed sql/queries/prune_memory_changes.sql
+	pruneMemoryChangesSQL string
+	//go:embed sql/queries/select_tags.sql
+	selectTagsSQL string
+	//go:embed sql/queries/update_memory.sql
+	updateMemorySQL string
+	//go:embed sql/queries/select_memories_page.sql
+	selectMemoriesPageSQL string
+	//go:embed sql/queries/count_memories.sql
+	countMemoriesSQL string
+	//go:embed sql/queries/insert_audit_entry.sql
+	insertAuditEntrySQL string
+	//go:embed sql/queries/select_audit_log.sql
+	selectAuditLogSQL string
 )
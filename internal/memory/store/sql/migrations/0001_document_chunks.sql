@@ -0,0 +1,10 @@
+-- Chunks of ingested documents are stored as memories. They share a
+-- document_id, are ordered by chunk_index and remember the file they came
+-- from. content_hash lets a re-ingest keep chunks whose text didn't change.
+
+ALTER TABLE memories ADD COLUMN document_id TEXT;
+ALTER TABLE memories ADD COLUMN chunk_index INTEGER;
+ALTER TABLE memories ADD COLUMN source_path TEXT;
+ALTER TABLE memories ADD COLUMN content_hash TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_memories_document ON memories(document_id, chunk_index);
@@ -0,0 +1,9 @@
+-- Extracted memories wait for review before retrieval may return them.
+-- status is 'active' (retrievable) or 'pending' (awaiting review).
+-- confidence is how sure the extractor was, from 0 to 1; NULL for memories
+-- that weren't extracted.
+
+ALTER TABLE memories ADD COLUMN status TEXT NOT NULL DEFAULT 'active';
+ALTER TABLE memories ADD COLUMN confidence REAL;
+
+CREATE INDEX IF NOT EXISTS idx_memories_status ON memories(status);
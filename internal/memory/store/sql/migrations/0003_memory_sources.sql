@@ -0,0 +1,12 @@
+-- Consolidation merges overlapping memories into one. memory_sources links
+-- the merged memory to the originals it replaced, which are kept with status
+-- 'archived' so retrieval no longer returns them.
+
+CREATE TABLE IF NOT EXISTS memory_sources (
+    memory_id TEXT NOT NULL,
+    source_id TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    PRIMARY KEY (memory_id, source_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_sources_source ON memory_sources(source_id);
@@ -0,0 +1,15 @@
+-- The profile document summarizes who the user is from their pinned and
+-- most important memories. pinned marks a memory the user wants in the
+-- profile whatever its importance; importance is from 0 to 1, NULL when unset.
+-- Each regeneration of the profile is kept as a new version, together with
+-- the IDs of the memories it was written from.
+
+ALTER TABLE memories ADD COLUMN pinned INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE memories ADD COLUMN importance REAL;
+
+CREATE TABLE IF NOT EXISTS profile_versions (
+    version INTEGER PRIMARY KEY,
+    content TEXT NOT NULL,
+    memory_ids TEXT NOT NULL DEFAULT '[]',
+    created_at INTEGER NOT NULL
+);
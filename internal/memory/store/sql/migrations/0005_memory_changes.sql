@@ -0,0 +1,29 @@
+-- memory_changes logs every change to a memory, whichever process made it,
+-- so long-running readers such as the MCP server can poll for changes and
+-- notify their clients. Rewriting a memory's embedding isn't a change to
+-- what it says and isn't logged. Old entries are pruned by the readers.
+
+CREATE TABLE IF NOT EXISTS memory_changes (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    memory_id TEXT NOT NULL,
+    op TEXT NOT NULL,
+    created_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_changes_created_at ON memory_changes(created_at);
+
+CREATE TRIGGER IF NOT EXISTS memories_changes_ai AFTER INSERT ON memories BEGIN
+    INSERT INTO memory_changes (memory_id, op, created_at)
+    VALUES (NEW.id, 'insert', CAST(strftime('%s', 'now') AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS memories_changes_au
+AFTER UPDATE OF text, tags, source, status, pinned, importance, chunk_index, source_path ON memories BEGIN
+    INSERT INTO memory_changes (memory_id, op, created_at)
+    VALUES (NEW.id, 'update', CAST(strftime('%s', 'now') AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS memories_changes_ad AFTER DELETE ON memories BEGIN
+    INSERT INTO memory_changes (memory_id, op, created_at)
+    VALUES (OLD.id, 'delete', CAST(strftime('%s', 'now') AS INTEGER));
+END;
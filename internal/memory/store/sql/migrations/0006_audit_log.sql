@@ -0,0 +1,16 @@
+-- audit_log keeps a copy of every memory deleted on request through MCP,
+-- with what asked for the deletion, so a wrong forget can be traced and the
+-- memory saved again by hand.
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    action TEXT NOT NULL,
+    memory_id TEXT NOT NULL,
+    text TEXT NOT NULL,
+    tags TEXT,
+    source TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
@@ -0,0 +1,5 @@
+-- memory_hashes records, by memory ID, a hash of what each memory a profile
+-- version was written from said, so that editing a pinned or important
+-- memory makes the profile stale too, not only adding or removing one.
+
+ALTER TABLE profile_versions ADD COLUMN memory_hashes TEXT NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+UPDATE memories SET status = 'archived'
+WHERE id = ? AND status = 'active' AND text = ?;
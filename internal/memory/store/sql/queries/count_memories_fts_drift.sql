@@ -0,0 +1,5 @@
+SELECT
+    (SELECT count(*) FROM memories),
+    (SELECT count(*) FROM memories_fts_docsize),
+    (SELECT count(*) FROM memories WHERE rowid NOT IN (SELECT id FROM memories_fts_docsize)),
+    (SELECT count(*) FROM memories_fts_docsize WHERE id NOT IN (SELECT rowid FROM memories));
@@ -0,0 +1 @@
+DELETE FROM memories WHERE id = ? AND document_id = ?;
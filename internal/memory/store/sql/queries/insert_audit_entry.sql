@@ -0,0 +1,4 @@
+INSERT INTO audit_log (action, memory_id, text, tags, source, reason, created_at)
+SELECT ?1, id, text, tags, source, ?2, ?3
+FROM memories
+WHERE id = ?4;
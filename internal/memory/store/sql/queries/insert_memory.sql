@@ -1,2 +1,3 @@
-INSERT INTO memories (id, text, tags, source, created_at, provider, model_id, dim, embedding)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+INSERT INTO memories (id, text, tags, source, created_at, provider, model_id, dim, embedding,
+                      document_id, chunk_index, source_path, content_hash, status, confidence, pinned, importance)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
@@ -0,0 +1,2 @@
+INSERT OR IGNORE INTO memory_sources (memory_id, source_id, created_at)
+VALUES (?, ?, ?);
@@ -0,0 +1,5 @@
+INSERT INTO profile_versions (version, content, memory_ids, memory_hashes, created_at)
+SELECT COALESCE(MAX(version), 0) + 1, ?, ?, ?, ?
+FROM profile_versions
+HAVING COALESCE(MAX(version), 0) = ?
+RETURNING version;
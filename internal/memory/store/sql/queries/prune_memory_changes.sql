@@ -0,0 +1 @@
+DELETE FROM memory_changes WHERE created_at < ?;
@@ -0,0 +1 @@
+INSERT INTO memories_fts(memories_fts) VALUES('rebuild');
@@ -1,9 +1,21 @@
 SELECT m.id, m.text, m.tags, m.source, m.created_at,
        m.provider, m.model_id, m.dim, m.embedding,
+       m.document_id, m.chunk_index, m.source_path, m.content_hash, m.status, m.confidence, m.pinned, m.importance,
        snippet(memories_fts, 0, '>>>', '<<<', '...', 32) as snippet,
        rank
 FROM memories m
 JOIN memories_fts fts ON m.rowid = fts.rowid
-WHERE memories_fts MATCH ?
+WHERE memories_fts MATCH ?1
+  AND m.status = 'active'
+  AND (?2 = '' OR m.source = ?2)
+  AND (?3 = 0 OR m.created_at >= ?3)
+  AND (?4 = 0 OR m.created_at < ?4)
+  AND NOT EXISTS (
+      SELECT 1 FROM json_each(?5) AS want
+      WHERE NOT EXISTS (
+          SELECT 1 FROM json_each(m.tags) AS have
+          WHERE lower(have.value) = lower(want.value)
+      )
+  )
 ORDER BY rank
-LIMIT ?;
+LIMIT ?6;
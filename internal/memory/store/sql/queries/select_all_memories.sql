@@ -1,3 +1,4 @@
-SELECT id, text, tags, source, created_at, provider, model_id, dim, embedding
+SELECT id, text, tags, source, created_at, provider, model_id, dim, embedding,
+       document_id, chunk_index, source_path, content_hash, status, confidence, pinned, importance
 FROM memories
 ORDER BY created_at DESC;
@@ -0,0 +1,4 @@
+SELECT id, action, memory_id, text, tags, source, reason, created_at
+FROM audit_log
+ORDER BY id DESC
+LIMIT ?;
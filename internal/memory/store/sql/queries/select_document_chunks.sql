@@ -0,0 +1,5 @@
+SELECT id, text, tags, source, created_at, provider, model_id, dim, embedding,
+       document_id, chunk_index, source_path, content_hash, status, confidence, pinned, importance
+FROM memories
+WHERE document_id = ?
+ORDER BY chunk_index;
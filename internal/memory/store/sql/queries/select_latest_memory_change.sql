@@ -0,0 +1 @@
+SELECT COALESCE(MAX(seq), 0) FROM memory_changes;
@@ -0,0 +1,4 @@
+SELECT version, content, memory_ids, memory_hashes, created_at
+FROM profile_versions
+ORDER BY version DESC
+LIMIT 1;
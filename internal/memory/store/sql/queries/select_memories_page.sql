@@ -0,0 +1,16 @@
+SELECT id, text, tags, source, created_at, provider, model_id, dim, embedding,
+       document_id, chunk_index, source_path, content_hash, status, confidence, pinned, importance
+FROM memories m
+WHERE (?1 = '' OR m.source = ?1)
+  AND (?2 = 0 OR m.created_at >= ?2)
+  AND (?3 = 0 OR m.created_at < ?3)
+  AND NOT EXISTS (
+      SELECT 1 FROM json_each(?4) AS want
+      WHERE NOT EXISTS (
+          SELECT 1 FROM json_each(m.tags) AS have
+          WHERE lower(have.value) = lower(want.value)
+      )
+  )
+  AND (?5 = '' OR m.status = ?5)
+ORDER BY created_at DESC, id
+LIMIT ?6 OFFSET ?7;
@@ -0,0 +1,4 @@
+SELECT id, text, tags, source, created_at, provider, model_id, dim, embedding,
+       document_id, chunk_index, source_path, content_hash, status, confidence, pinned, importance
+FROM memories
+WHERE id = ?;
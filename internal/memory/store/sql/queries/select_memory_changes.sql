@@ -0,0 +1,5 @@
+SELECT seq, memory_id, op, created_at
+FROM memory_changes
+WHERE seq > ?
+ORDER BY seq
+LIMIT ?;
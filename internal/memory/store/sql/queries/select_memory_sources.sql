@@ -0,0 +1,6 @@
+SELECT m.id, m.text, m.tags, m.source, m.created_at, m.provider, m.model_id, m.dim, m.embedding,
+       m.document_id, m.chunk_index, m.source_path, m.content_hash, m.status, m.confidence, m.pinned, m.importance
+FROM memory_sources ms
+JOIN memories m ON m.id = ms.source_id
+WHERE ms.memory_id = ?
+ORDER BY m.created_at;
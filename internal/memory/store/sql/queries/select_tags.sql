@@ -0,0 +1,5 @@
+SELECT MIN(t.value) AS tag, COUNT(*) AS uses
+FROM memories m, json_each(m.tags) AS t
+WHERE m.status = 'active' AND t.type = 'text'
+GROUP BY lower(t.value)
+ORDER BY uses DESC, tag;
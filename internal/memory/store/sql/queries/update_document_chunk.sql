@@ -0,0 +1,3 @@
+UPDATE memories
+SET chunk_index = ?, source_path = ?, tags = ?
+WHERE id = ? AND document_id = ?;
@@ -0,0 +1,3 @@
+UPDATE memories
+SET text = ?, tags = ?, provider = ?, model_id = ?, dim = ?, embedding = ?
+WHERE id = ?;
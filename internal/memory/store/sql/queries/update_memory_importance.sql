@@ -0,0 +1 @@
+UPDATE memories SET importance = ? WHERE id = ?;
@@ -0,0 +1 @@
+UPDATE memories SET pinned = ? WHERE id = ?;
@@ -0,0 +1 @@
+UPDATE memories SET status = ? WHERE id = ?;
@@ -13,6 +13,7 @@ import (
 
 	"github.com/austiecodes/gomor/internal/memory/memtypes"
 	"github.com/austiecodes/gomor/internal/memory/memutils"
+	"github.com/austiecodes/gomor/internal/types"
 	"github.com/austiecodes/gomor/internal/utils"
 )
 
@@ -23,11 +24,19 @@ type HistoryItem = memtypes.HistoryItem
 type SearchResult = memtypes.SearchResult
 type MemoryFTSResult = memtypes.MemoryFTSResult
 type HistorySearchResult = memtypes.HistorySearchResult
+type MemoryFilter = memtypes.MemoryFilter
+type ModelMismatch = memtypes.ModelMismatch
+type MemoryStatus = memtypes.MemoryStatus
+type Profile = memtypes.Profile
 
 // Re-export constants from memtypes for convenience
 const (
 	SourceExplicit  = memtypes.SourceExplicit
 	SourceExtracted = memtypes.SourceExtracted
+	SourceDocument  = memtypes.SourceDocument
+	StatusActive    = memtypes.StatusActive
+	StatusPending   = memtypes.StatusPending
+	StatusArchived  = memtypes.StatusArchived
 )
 
 // Re-export vector utils from memutils for convenience
@@ -38,9 +47,18 @@ var (
 	BytesToVector   = memutils.BytesToVector
 )
 
+// SQLite connection settings. WAL lets readers run alongside a writer, and the
+// busy timeout makes concurrent writers from other gomor processes wait for the
+// write lock instead of failing with SQLITE_BUSY.
+const (
+	busyTimeoutMs = 10000
+	maxOpenConns  = 4
+)
+
 // Store manages memory and history persistence in SQLite.
 type Store struct {
-	db *sql.DB
+	db   *sql.DB
+	path string
 }
 
 // NewStore creates a new memory store, initializing the database if needed.
@@ -49,13 +67,22 @@ func NewStore() (*Store, error) {
 	if err != nil {
 		return nil, err
 	}
+	return NewStoreWithPath(dbPath)
+}
 
-	db, err := sql.Open("sqlite", dbPath)
+// NewStoreWithPath opens the memory store at the given database file path.
+func NewStoreWithPath(dbPath string) (*Store, error) {
+	dsn := fmt.Sprintf("%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_txlock=immediate",
+		dbPath, busyTimeoutMs)
+
+	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open memory database: %w", err)
 	}
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxOpenConns)
 
-	store := &Store{db: db}
+	store := &Store{db: db, path: dbPath}
 	if err := store.initSchema(); err != nil {
 		db.Close()
 		return nil, err
@@ -82,12 +109,13 @@ func (s *Store) Close() error {
 	return nil
 }
 
-// initSchema creates the database tables if they don't exist.
+// initSchema creates the database tables if they don't exist and applies
+// pending migrations.
 func (s *Store) initSchema() error {
 	if _, err := s.db.Exec(schemaSQL); err != nil {
 		return fmt.Errorf("failed to initialize schema: %w", err)
 	}
-	return nil
+	return s.migrate()
 }
 
 // SaveMemory saves a new memory item with its embedding.
@@ -99,6 +127,16 @@ func (s *Store) SaveMemory(item *MemoryItem) error {
 		item.CreatedAt = time.Now()
 	}
 
+	return insertMemory(s.db, item)
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// insertMemory inserts item, whose ID and CreatedAt are already set.
+func insertMemory(db execer, item *MemoryItem) error {
 	tagsJSON, err := json.Marshal(item.Tags)
 	if err != nil {
 		return fmt.Errorf("failed to marshal tags: %w", err)
@@ -106,9 +144,27 @@ func (s *Store) SaveMemory(item *MemoryItem) error {
 
 	embeddingBytes := VectorToBytes(item.Embedding)
 
-	_, err = s.db.Exec(insertMemorySQL,
+	// Document columns stay NULL for standalone memories
+	var documentID, chunkIndex, sourcePath, contentHash any
+	if item.DocumentID != "" {
+		documentID, chunkIndex, sourcePath, contentHash = item.DocumentID, item.ChunkIndex, item.SourcePath, item.ContentHash
+	}
+	if item.Status == "" {
+		item.Status = StatusActive
+	}
+	var confidence, importance any
+	if item.Confidence > 0 {
+		confidence = item.Confidence
+	}
+	if item.Importance > 0 {
+		importance = item.Importance
+	}
+
+	_, err = db.Exec(insertMemorySQL,
 		item.ID, item.Text, string(tagsJSON), string(item.Source),
-		item.CreatedAt.Unix(), item.Provider, item.ModelID, item.Dim, embeddingBytes)
+		item.CreatedAt.Unix(), item.Provider, item.ModelID, item.Dim, embeddingBytes,
+		documentID, chunkIndex, sourcePath, contentHash, string(item.Status), confidence,
+		item.Pinned, importance)
 
 	if err != nil {
 		return fmt.Errorf("failed to save memory: %w", err)
@@ -127,56 +183,213 @@ func (s *Store) UpdateMemoryEmbedding(id string, embedding []float32, modelID st
 	return nil
 }
 
-// GetAllMemories returns all memory items (for vector search).
+// GetMemory returns the memory with the given ID, whatever its status, or
+// nil if there is none.
+func (s *Store) GetMemory(id string) (*MemoryItem, error) {
+	rows, err := s.db.Query(selectMemorySQL, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query memory: %w", err)
+	}
+	memories, err := scanMemories(rows)
+	if err != nil || len(memories) == 0 {
+		return nil, err
+	}
+	return &memories[0], nil
+}
+
+// GetAllMemories returns all memory items, including those pending review.
 func (s *Store) GetAllMemories() ([]MemoryItem, error) {
 	rows, err := s.db.Query(selectAllMemoriesSQL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query memories: %w", err)
 	}
+	return scanMemories(rows)
+}
+
+// GetMemories returns the active memory items matching filter, newest first.
+func (s *Store) GetMemories(filter MemoryFilter) ([]MemoryItem, error) {
+	args, err := filterArgs(filter)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.Query(selectFilteredMemoriesSQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query memories: %w", err)
+	}
+	return scanMemories(rows)
+}
+
+// ListMemories returns one page of the memories matching filter, newest
+// first, and how many match in total. An empty status lists every status.
+func (s *Store) ListMemories(filter MemoryFilter, status MemoryStatus, limit, offset int) ([]MemoryItem, int, error) {
+	args, err := filterArgs(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	args = append(args, string(status))
+
+	var total int
+	if err := s.db.QueryRow(countMemoriesSQL, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count memories: %w", err)
+	}
+
+	rows, err := s.db.Query(selectMemoriesPageSQL, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query memories: %w", err)
+	}
+	memories, err := scanMemories(rows)
+	return memories, total, err
+}
+
+// GetTags returns the tags of active memories, most used first. Tags that
+// differ only in case are returned once.
+func (s *Store) GetTags() ([]string, error) {
+	rows, err := s.db.Query(selectTagsSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
 	defer rows.Close()
 
-	var memories []MemoryItem
+	var tags []string
 	for rows.Next() {
-		var item MemoryItem
-		var tagsJSON string
-		var createdAtUnix int64
-		var embeddingBytes []byte
-		var source string
+		var tag string
+		var uses int
+		if err := rows.Scan(&tag, &uses); err != nil {
+			return nil, fmt.Errorf("failed to scan tag row: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
 
-		err := rows.Scan(&item.ID, &item.Text, &tagsJSON, &source,
-			&createdAtUnix, &item.Provider, &item.ModelID, &item.Dim, &embeddingBytes)
-		if err != nil {
+// scanMemories reads memory rows selected with the standard column list and closes rows.
+func scanMemories(rows *sql.Rows) ([]MemoryItem, error) {
+	defer rows.Close()
+
+	var memories []MemoryItem
+	for rows.Next() {
+		var row memoryRow
+		if err := rows.Scan(row.dest()...); err != nil {
 			return nil, fmt.Errorf("failed to scan memory row: %w", err)
 		}
+		memories = append(memories, row.memory())
+	}
 
-		item.Source = MemorySource(source)
-		item.CreatedAt = time.Unix(createdAtUnix, 0)
-		item.Embedding = BytesToVector(embeddingBytes)
+	return memories, rows.Err()
+}
 
-		if err := json.Unmarshal([]byte(tagsJSON), &item.Tags); err != nil {
-			item.Tags = nil // ignore malformed tags
-		}
+// memoryRow receives the standard memory column list:
+// id, text, tags, source, created_at, provider, model_id, dim, embedding,
+// document_id, chunk_index, source_path, content_hash, status, confidence,
+// pinned, importance.
+type memoryRow struct {
+	item           MemoryItem
+	tagsJSON       string
+	source         string
+	createdAtUnix  int64
+	embeddingBytes []byte
+	documentID     sql.NullString
+	chunkIndex     sql.NullInt64
+	sourcePath     sql.NullString
+	contentHash    sql.NullString
+	status         string
+	confidence     sql.NullFloat64
+	importance     sql.NullFloat64
+}
+
+// dest returns the scan destinations for the standard column list.
+func (r *memoryRow) dest() []any {
+	return []any{&r.item.ID, &r.item.Text, &r.tagsJSON, &r.source,
+		&r.createdAtUnix, &r.item.Provider, &r.item.ModelID, &r.item.Dim, &r.embeddingBytes,
+		&r.documentID, &r.chunkIndex, &r.sourcePath, &r.contentHash, &r.status, &r.confidence,
+		&r.item.Pinned, &r.importance}
+}
 
-		memories = append(memories, item)
+// memory converts the scanned columns into a MemoryItem.
+func (r *memoryRow) memory() MemoryItem {
+	item := r.item
+	item.Source = MemorySource(r.source)
+	item.CreatedAt = time.Unix(r.createdAtUnix, 0)
+	item.Embedding = BytesToVector(r.embeddingBytes)
+	item.DocumentID = r.documentID.String
+	item.ChunkIndex = int(r.chunkIndex.Int64)
+	item.SourcePath = r.sourcePath.String
+	item.ContentHash = r.contentHash.String
+	item.Status = MemoryStatus(r.status)
+	item.Confidence = r.confidence.Float64
+	item.Importance = r.importance.Float64
+
+	if err := json.Unmarshal([]byte(r.tagsJSON), &item.Tags); err != nil {
+		item.Tags = nil // ignore malformed tags
 	}
+	return item
+}
 
-	return memories, rows.Err()
+// filterArgs returns the source, created-after, created-before and tags
+// parameters shared by the filtered memory queries, in that order.
+// Zero values disable the corresponding condition.
+func filterArgs(filter MemoryFilter) ([]any, error) {
+	tags := filter.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tag filter: %w", err)
+	}
+
+	var after, before int64
+	if !filter.CreatedAfter.IsZero() {
+		after = filter.CreatedAfter.Unix()
+	}
+	if !filter.CreatedBefore.IsZero() {
+		before = filter.CreatedBefore.Unix()
+	}
+
+	return []any{string(filter.Source), after, before, string(tagsJSON)}, nil
+}
+
+// SearchStats describes how a vector search treated the stored memories.
+type SearchStats struct {
+	Scanned    int             // memories matching the filter
+	Mismatched []ModelMismatch // memories skipped, grouped by model and dimension
+}
+
+// MismatchedCount returns the total number of skipped memories.
+func (st SearchStats) MismatchedCount() int {
+	var n int
+	for _, m := range st.Mismatched {
+		n += m.Count
+	}
+	return n
 }
 
-// SearchMemories performs vector similarity search on memories.
+// SearchMemories performs vector similarity search on active memories matching filter.
+// Only memories embedded with model and with the query's dimension are scored;
+// the rest are skipped and counted in the returned stats, since vectors from
+// different models are not comparable. An empty model only checks dimensions.
 // Returns top K results with similarity >= minSimilarity.
-func (s *Store) SearchMemories(queryEmbedding []float32, topK int, minSimilarity float64) ([]SearchResult, error) {
-	memories, err := s.GetAllMemories()
+func (s *Store) SearchMemories(queryEmbedding []float32, model types.Model, topK int, minSimilarity float64, filter MemoryFilter) ([]SearchResult, SearchStats, error) {
+	var stats SearchStats
+
+	memories, err := s.GetMemories(filter)
 	if err != nil {
-		return nil, err
+		return nil, stats, err
 	}
+	stats.Scanned = len(memories)
 
 	// Normalize query embedding for cosine similarity via dot product
 	normalizedQuery := NormalizeVector(queryEmbedding)
 
 	// Calculate similarities
 	var results []SearchResult
+	mismatched := make(map[ModelMismatch]int)
 	for _, mem := range memories {
+		if !embeddedWith(mem, model, len(normalizedQuery)) {
+			mismatched[ModelMismatch{Provider: mem.Provider, ModelID: mem.ModelID, Dim: len(mem.Embedding)}]++
+			continue
+		}
+
 		// Embeddings are stored normalized, so dot product = cosine similarity
 		similarity := DotProduct(normalizedQuery, mem.Embedding)
 		if similarity >= minSimilarity {
@@ -187,6 +400,21 @@ func (s *Store) SearchMemories(queryEmbedding []float32, topK int, minSimilarity
 		}
 	}
 
+	for key, count := range mismatched {
+		key.Count = count
+		stats.Mismatched = append(stats.Mismatched, key)
+	}
+	sort.Slice(stats.Mismatched, func(i, j int) bool {
+		a, b := stats.Mismatched[i], stats.Mismatched[j]
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.ModelID != b.ModelID {
+			return a.ModelID < b.ModelID
+		}
+		return a.Dim < b.Dim
+	})
+
 	// Sort by similarity descending
 	sort.Slice(results, func(i, j int) bool {
 		return results[i].Similarity > results[j].Similarity
@@ -197,7 +425,50 @@ func (s *Store) SearchMemories(queryEmbedding []float32, topK int, minSimilarity
 		results = results[:topK]
 	}
 
-	return results, nil
+	return results, stats, nil
+}
+
+// embeddedWith reports whether mem's vector is comparable with a query vector
+// of dimension dim produced by model.
+func embeddedWith(mem MemoryItem, model types.Model, dim int) bool {
+	if len(mem.Embedding) != dim {
+		return false
+	}
+	if model.Provider == "" && model.ModelID == "" {
+		return true
+	}
+	return mem.Provider == model.Provider && mem.ModelID == model.ModelID
+}
+
+// UpdateMemory changes the text and tags of a memory in place, with the
+// embedding of the new text. Its ID, creation time, status and pin are kept.
+func (s *Store) UpdateMemory(item *MemoryItem) error {
+	tagsJSON, err := json.Marshal(item.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	res, err := s.db.Exec(updateMemorySQL, item.Text, string(tagsJSON),
+		item.Provider, item.ModelID, item.Dim, VectorToBytes(item.Embedding), item.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update memory: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("memory %s not found", item.ID)
+	}
+	return nil
+}
+
+// SetMemoryStatus changes the status of a memory, e.g. to approve one that
+// is pending review.
+func (s *Store) SetMemoryStatus(id string, status MemoryStatus) error {
+	res, err := s.db.Exec(updateMemoryStatusSQL, string(status), id)
+	if err != nil {
+		return fmt.Errorf("failed to update memory status: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("memory %s not found", id)
+	}
+	return nil
 }
 
 // DeleteMemory deletes a memory by ID.
@@ -206,10 +477,17 @@ func (s *Store) DeleteMemory(id string) error {
 	return err
 }
 
-// SearchMemoriesFTS performs full-text search on memory text.
+// SearchMemoriesFTS performs full-text search on the text of active memories matching filter.
 // Returns top K results ordered by FTS rank.
-func (s *Store) SearchMemoriesFTS(query string, topK int) ([]MemoryFTSResult, error) {
-	rows, err := s.db.Query(searchMemoriesFTSSQL, query, topK)
+func (s *Store) SearchMemoriesFTS(query string, topK int, filter MemoryFilter) ([]MemoryFTSResult, error) {
+	args, err := filterArgs(filter)
+	if err != nil {
+		return nil, err
+	}
+	args = append([]any{query}, args...)
+	args = append(args, topK)
+
+	rows, err := s.db.Query(searchMemoriesFTSSQL, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search memories FTS: %w", err)
 	}
@@ -217,29 +495,15 @@ func (s *Store) SearchMemoriesFTS(query string, topK int) ([]MemoryFTSResult, er
 
 	var results []MemoryFTSResult
 	for rows.Next() {
-		var item MemoryItem
+		var row memoryRow
 		var result MemoryFTSResult
-		var tagsJSON string
-		var createdAtUnix int64
-		var embeddingBytes []byte
-		var source string
 
-		err := rows.Scan(&item.ID, &item.Text, &tagsJSON, &source,
-			&createdAtUnix, &item.Provider, &item.ModelID, &item.Dim, &embeddingBytes,
-			&result.Snippet, &result.Rank)
+		err := rows.Scan(append(row.dest(), &result.Snippet, &result.Rank)...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan memory FTS row: %w", err)
 		}
 
-		item.Source = MemorySource(source)
-		item.CreatedAt = time.Unix(createdAtUnix, 0)
-		item.Embedding = BytesToVector(embeddingBytes)
-
-		if err := json.Unmarshal([]byte(tagsJSON), &item.Tags); err != nil {
-			item.Tags = nil // ignore malformed tags
-		}
-
-		result.Item = item
+		result.Item = row.memory()
 		results = append(results, result)
 	}
 
@@ -299,12 +563,26 @@ func (s *Store) SearchHistory(query string, topK int) ([]HistorySearchResult, er
 	return results, rows.Err()
 }
 
-// GetRecentHistory returns the most recent history items.
+// GetRecentHistory returns the most recent history items, newest first.
 func (s *Store) GetRecentHistory(limit int) ([]HistoryItem, error) {
 	rows, err := s.db.Query(selectRecentHistorySQL, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query recent history: %w", err)
 	}
+	return scanHistory(rows)
+}
+
+// GetSessionHistory returns the most recent history items of one session, newest first.
+func (s *Store) GetSessionHistory(sessionID string, limit int) ([]HistoryItem, error) {
+	rows, err := s.db.Query(selectSessionHistorySQL, sessionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query session history: %w", err)
+	}
+	return scanHistory(rows)
+}
+
+// scanHistory reads history rows (id, role, content, created_at, session_id) and closes rows.
+func scanHistory(rows *sql.Rows) ([]HistoryItem, error) {
 	defer rows.Close()
 
 	var items []HistoryItem
@@ -0,0 +1,565 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/austiecodes/gomor/internal/types"
+)
+
+const (
+	stressWriterProcesses = 4
+	stressWritesPerWorker = 25
+	stressWorkersPerProc  = 3
+)
+
+// TestConcurrentWriterProcesses spawns several processes that write to the same
+// database at once, mimicking one `gomor mcp` per IDE window, while this process
+// keeps reading. No write may fail with SQLITE_BUSY.
+func TestConcurrentWriterProcesses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping multi-process stress test in short mode")
+	}
+
+	dbPath := filepath.Join(t.TempDir(), "memory.db")
+	s, err := NewStoreWithPath(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Keep reading while the writers run.
+	readErrCh := make(chan error, 1)
+	go func() {
+		for ctx.Err() == nil {
+			if _, err := s.SearchMemoriesFTS("stress", 10, MemoryFilter{}); err != nil {
+				readErrCh <- err
+				return
+			}
+			if _, err := s.GetAllMemories(); err != nil {
+				readErrCh <- err
+				return
+			}
+		}
+		readErrCh <- nil
+	}()
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, stressWriterProcesses)
+	for i := 0; i < stressWriterProcesses; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			cmd := exec.Command(os.Args[0], "-test.run=^TestStressWriterProcess$", "-test.v")
+			cmd.Env = append(os.Environ(),
+				"GOMOR_STRESS_DB="+dbPath,
+				"GOMOR_STRESS_WRITER="+strconv.Itoa(id),
+			)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				errCh <- fmt.Errorf("writer %d: %v\n%s", id, err, out)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errCh)
+	for err := range errCh {
+		t.Error(err)
+	}
+
+	cancel()
+	if err := <-readErrCh; err != nil {
+		t.Errorf("concurrent read failed: %v", err)
+	}
+
+	memories, err := s.GetAllMemories()
+	if err != nil {
+		t.Fatalf("get all memories: %v", err)
+	}
+	want := stressWriterProcesses * stressWorkersPerProc * stressWritesPerWorker
+	if len(memories) != want {
+		t.Fatalf("expected %d memories, got %d", want, len(memories))
+	}
+}
+
+// TestStressWriterProcess is the body of a writer process spawned by
+// TestConcurrentWriterProcesses. It is skipped when run directly.
+func TestStressWriterProcess(t *testing.T) {
+	dbPath := os.Getenv("GOMOR_STRESS_DB")
+	if dbPath == "" {
+		t.Skip("helper process for TestConcurrentWriterProcesses")
+	}
+	writer := os.Getenv("GOMOR_STRESS_WRITER")
+
+	s, err := NewStoreWithPath(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	var wg sync.WaitGroup
+	for w := 0; w < stressWorkersPerProc; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < stressWritesPerWorker; i++ {
+				item := &MemoryItem{
+					Text:      fmt.Sprintf("stress memory %s-%d-%d", writer, worker, i),
+					Source:    SourceExplicit,
+					Provider:  "fake",
+					ModelID:   "fake-embed",
+					Dim:       2,
+					Embedding: []float32{1, 0},
+				}
+				if err := s.SaveMemory(item); err != nil {
+					t.Errorf("save memory: %v", err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// TestMaintenanceLock checks that the maintenance lock is exclusive and is
+// released properly.
+func TestMaintenanceLock(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "memory.db")
+	s1, err := NewStoreWithPath(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s1.Close()
+	s2, err := NewStoreWithPath(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s2.Close()
+
+	lock, err := s1.AcquireMaintenanceLock(context.Background())
+	if err != nil {
+		t.Fatalf("acquire lock: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if _, err := s2.AcquireMaintenanceLock(ctx); err == nil {
+		t.Fatal("expected second acquisition to fail while the lock is held")
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("release lock: %v", err)
+	}
+
+	lock2, err := s2.AcquireMaintenanceLock(context.Background())
+	if err != nil {
+		t.Fatalf("acquire lock after release: %v", err)
+	}
+	if err := lock2.Release(); err != nil {
+		t.Fatalf("release lock: %v", err)
+	}
+}
+
+// TestSearchMemoriesFilter checks that tag, source and date filters are applied
+// by both the vector and the FTS search.
+func TestSearchMemoriesFilter(t *testing.T) {
+	s, err := NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	jan := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	mar := time.Date(2025, 3, 15, 12, 0, 0, 0, time.UTC)
+	memories := []*MemoryItem{
+		{ID: "go-jan", Text: "editor prefers gofmt on save", Tags: []string{"Go", "editor"}, Source: SourceExplicit, CreatedAt: jan},
+		{ID: "go-mar", Text: "editor uses gopls with staticcheck", Tags: []string{"go"}, Source: SourceExtracted, CreatedAt: mar},
+		{ID: "py-mar", Text: "editor formats python with black", Tags: []string{"python", "editor"}, Source: SourceExplicit, CreatedAt: mar},
+		{ID: "untagged", Text: "editor theme is solarized", Source: SourceExplicit, CreatedAt: mar},
+	}
+	for _, m := range memories {
+		m.Provider, m.ModelID, m.Dim, m.Embedding = "fake", "fake-embed", 2, []float32{1, 0}
+		if err := s.SaveMemory(m); err != nil {
+			t.Fatalf("save memory: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter MemoryFilter
+		want   []string
+	}{
+		{"no filter", MemoryFilter{}, []string{"go-jan", "go-mar", "py-mar", "untagged"}},
+		{"tag case-insensitive", MemoryFilter{Tags: []string{"GO"}}, []string{"go-jan", "go-mar"}},
+		{"all tags required", MemoryFilter{Tags: []string{"go", "editor"}}, []string{"go-jan"}},
+		{"source", MemoryFilter{Source: SourceExtracted}, []string{"go-mar"}},
+		{"created after", MemoryFilter{CreatedAfter: mar}, []string{"go-mar", "py-mar", "untagged"}},
+		{"created before", MemoryFilter{CreatedBefore: mar}, []string{"go-jan"}},
+		{"combined", MemoryFilter{Tags: []string{"editor"}, Source: SourceExplicit, CreatedAfter: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)}, []string{"py-mar"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vector, _, err := s.SearchMemories([]float32{1, 0}, types.Model{Provider: "fake", ModelID: "fake-embed"}, 10, 0, tt.filter)
+			if err != nil {
+				t.Fatalf("vector search: %v", err)
+			}
+			fts, err := s.SearchMemoriesFTS("editor", 10, tt.filter)
+			if err != nil {
+				t.Fatalf("fts search: %v", err)
+			}
+
+			var vectorIDs, ftsIDs []string
+			for _, r := range vector {
+				vectorIDs = append(vectorIDs, r.Item.ID)
+			}
+			for _, r := range fts {
+				ftsIDs = append(ftsIDs, r.Item.ID)
+			}
+			sort.Strings(vectorIDs)
+			sort.Strings(ftsIDs)
+			if !slices.Equal(vectorIDs, tt.want) {
+				t.Errorf("vector search: got %v want %v", vectorIDs, tt.want)
+			}
+			if !slices.Equal(ftsIDs, tt.want) {
+				t.Errorf("fts search: got %v want %v", ftsIDs, tt.want)
+			}
+		})
+	}
+}
+
+// TestSearchMemoriesSkipsMismatchedModels checks that vectors from another
+// model or with another dimension are skipped and reported, not scored as 0.
+func TestSearchMemoriesSkipsMismatchedModels(t *testing.T) {
+	s, err := NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	memories := []*MemoryItem{
+		{ID: "current", ModelID: "embed-v2", Embedding: []float32{1, 0}},
+		{ID: "old-1", ModelID: "embed-v1", Embedding: []float32{1, 0, 0}},
+		{ID: "old-2", ModelID: "embed-v1", Embedding: []float32{0, 1, 0}},
+		{ID: "wrong-dim", ModelID: "embed-v2", Embedding: []float32{1, 0, 0}},
+	}
+	for _, m := range memories {
+		m.Text, m.Source, m.Provider, m.Dim = m.ID, SourceExplicit, "fake", len(m.Embedding)
+		if err := s.SaveMemory(m); err != nil {
+			t.Fatalf("save memory: %v", err)
+		}
+	}
+
+	results, stats, err := s.SearchMemories([]float32{1, 0}, types.Model{Provider: "fake", ModelID: "embed-v2"}, 10, -1, MemoryFilter{})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 1 || results[0].Item.ID != "current" {
+		t.Fatalf("expected only the current-model memory, got %+v", results)
+	}
+
+	want := []ModelMismatch{
+		{Provider: "fake", ModelID: "embed-v1", Dim: 3, Count: 2},
+		{Provider: "fake", ModelID: "embed-v2", Dim: 3, Count: 1},
+	}
+	if stats.Scanned != 4 || !slices.Equal(stats.Mismatched, want) || stats.MismatchedCount() != 3 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+// TestMigrations checks that a database created before migrations existed is
+// upgraded in place, keeps its memories, and is not migrated twice.
+func TestMigrations(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "memory.db")
+
+	// A database as older gomor versions created it: schema only, user_version 0
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if _, err := db.Exec(schemaSQL); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO memories (id, text, tags, source, created_at, provider, model_id, dim, embedding)
+		VALUES ('old', 'uses tabs', '[]', 'explicit', 1, 'fake', 'fake-embed', 2, ?)`, VectorToBytes([]float32{1, 0})); err != nil {
+		t.Fatalf("insert memory: %v", err)
+	}
+	db.Close()
+
+	for i := 0; i < 2; i++ {
+		s, err := NewStoreWithPath(dbPath)
+		if err != nil {
+			t.Fatalf("open store (pass %d): %v", i, err)
+		}
+		var version int
+		if err := s.db.QueryRow(selectUserVersionSQL).Scan(&version); err != nil {
+			t.Fatalf("read version: %v", err)
+		}
+		entries, _ := migrationsFS.ReadDir(migrationsDir)
+		if version != len(entries) {
+			t.Fatalf("expected user_version %d, got %d", len(entries), version)
+		}
+
+		memories, err := s.GetAllMemories()
+		if err != nil || len(memories) != 1 || memories[0].ID != "old" || memories[0].DocumentID != "" {
+			t.Fatalf("expected the old memory to survive, got %+v (%v)", memories, err)
+		}
+		s.Close()
+	}
+}
+
+// TestPinnedAndImportance checks that pin and importance round-trip and that
+// updating a missing memory fails.
+func TestPinnedAndImportance(t *testing.T) {
+	s, err := NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	item := &MemoryItem{ID: "m", Text: "prefers tabs", Source: SourceExplicit, Importance: 0.4}
+	if err := s.SaveMemory(item); err != nil {
+		t.Fatalf("save memory: %v", err)
+	}
+	if err := s.SetMemoryPinned("m", true); err != nil {
+		t.Fatalf("pin: %v", err)
+	}
+	if err := s.SetMemoryImportance("m", 0.9); err != nil {
+		t.Fatalf("set importance: %v", err)
+	}
+
+	memories, err := s.GetMemories(MemoryFilter{})
+	if err != nil || len(memories) != 1 || !memories[0].Pinned || memories[0].Importance != 0.9 {
+		t.Fatalf("expected a pinned memory with importance 0.9, got %+v (%v)", memories, err)
+	}
+
+	if err := s.SetMemoryPinned("missing", true); err == nil {
+		t.Fatal("expected an error pinning a missing memory")
+	}
+	if err := s.SetMemoryImportance("m", 1.5); err == nil {
+		t.Fatal("expected an error for importance above 1")
+	}
+}
+
+// TestMemoryChanges checks that inserts, updates and deletes are logged and
+// that re-embedding a memory is not.
+func TestMemoryChanges(t *testing.T) {
+	s, err := NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	start, err := s.LatestMemoryChange()
+	if err != nil {
+		t.Fatalf("latest change: %v", err)
+	}
+
+	item := &MemoryItem{ID: "m", Text: "prefers tabs", Source: SourceExplicit, Embedding: []float32{1, 0}}
+	if err := s.SaveMemory(item); err != nil {
+		t.Fatalf("save memory: %v", err)
+	}
+	if err := s.UpdateMemoryEmbedding("m", []float32{0, 1}, "other", 2, "fake"); err != nil {
+		t.Fatalf("update embedding: %v", err)
+	}
+	if err := s.SetMemoryPinned("m", true); err != nil {
+		t.Fatalf("pin: %v", err)
+	}
+	if err := s.DeleteMemory("m"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	changes, err := s.GetMemoryChanges(start, 10)
+	if err != nil {
+		t.Fatalf("get changes: %v", err)
+	}
+	var ops []string
+	for _, c := range changes {
+		if c.MemoryID != "m" {
+			t.Fatalf("unexpected memory in change %+v", c)
+		}
+		ops = append(ops, c.Op)
+	}
+	if want := []string{ChangeInsert, ChangeUpdate, ChangeDelete}; !slices.Equal(ops, want) {
+		t.Fatalf("got changes %v, want %v", ops, want)
+	}
+
+	if m, err := s.GetMemory("m"); err != nil || m != nil {
+		t.Fatalf("expected deleted memory to be gone, got %+v (%v)", m, err)
+	}
+
+	if err := s.PruneMemoryChanges(time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if changes, err := s.GetMemoryChanges(0, 10); err != nil || len(changes) != 0 {
+		t.Fatalf("expected pruned log, got %+v (%v)", changes, err)
+	}
+}
+
+// TestListAndUpdateMemories checks paging, the status filter and in-place updates.
+func TestListAndUpdateMemories(t *testing.T) {
+	s, err := NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	for i, status := range []MemoryStatus{StatusActive, StatusActive, StatusPending, StatusActive} {
+		m := &MemoryItem{
+			ID: fmt.Sprintf("m%d", i), Text: "memory", Tags: []string{"go"}, Source: SourceExplicit, Status: status,
+			CreatedAt: time.Unix(int64(1000+i), 0), Provider: "fake", ModelID: "fake-embed", Dim: 2, Embedding: []float32{1, 0},
+		}
+		if err := s.SaveMemory(m); err != nil {
+			t.Fatalf("save memory: %v", err)
+		}
+	}
+
+	page, total, err := s.ListMemories(MemoryFilter{Tags: []string{"GO"}}, StatusActive, 2, 1)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var ids []string
+	for _, m := range page {
+		ids = append(ids, m.ID)
+	}
+	if total != 3 || !slices.Equal(ids, []string{"m1", "m0"}) {
+		t.Fatalf("expected m1, m0 of 3, got %v of %d", ids, total)
+	}
+	if _, total, err := s.ListMemories(MemoryFilter{}, "", 10, 0); err != nil || total != 4 {
+		t.Fatalf("expected all 4 memories, got %d (%v)", total, err)
+	}
+
+	update := &MemoryItem{ID: "m0", Text: "updated", Tags: []string{"rust"}, Provider: "fake", ModelID: "fake-embed", Dim: 2, Embedding: []float32{0, 1}}
+	if err := s.UpdateMemory(update); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	m, err := s.GetMemory("m0")
+	if err != nil || m.Text != "updated" || !slices.Equal(m.Tags, []string{"rust"}) || m.Embedding[1] != 1 || m.CreatedAt.Unix() != 1000 {
+		t.Fatalf("unexpected memory after update: %+v (%v)", m, err)
+	}
+	if fts, err := s.SearchMemoriesFTS("updated", 10, MemoryFilter{}); err != nil || len(fts) != 1 {
+		t.Fatalf("expected the new text to be searchable, got %d results (%v)", len(fts), err)
+	}
+
+	update.ID = "missing"
+	if err := s.UpdateMemory(update); err == nil {
+		t.Fatal("expected an error updating a missing memory")
+	}
+}
+
+func TestDeleteMemoriesAudited(t *testing.T) {
+	s, err := NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	for _, item := range []*MemoryItem{
+		{ID: "job", Text: "Works at Acme", Tags: []string{"work"}, Source: SourceExplicit},
+		{ID: "team", Text: "Leads the Acme platform team", Source: SourceExtracted},
+		{ID: "editor", Text: "Uses Vim", Source: SourceExplicit},
+	} {
+		if err := s.SaveMemory(item); err != nil {
+			t.Fatalf("save memory: %v", err)
+		}
+	}
+
+	deleted, err := s.DeleteMemoriesAudited([]string{"job", "team", "missing"}, AuditForget, "my old job")
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if !slices.Equal(deleted, []string{"job", "team"}) {
+		t.Fatalf("got deleted %v", deleted)
+	}
+	if m, err := s.GetMemory("job"); err != nil || m != nil {
+		t.Fatalf("expected job to be deleted, got %v, %v", m, err)
+	}
+	if m, err := s.GetMemory("editor"); err != nil || m == nil {
+		t.Fatalf("expected editor to be kept, got %v, %v", m, err)
+	}
+
+	entries, err := s.GetAuditLog(10)
+	if err != nil {
+		t.Fatalf("audit log: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d", len(entries))
+	}
+	e := entries[1]
+	if e.MemoryID != "job" || e.Action != AuditForget || e.Reason != "my old job" ||
+		e.Text != "Works at Acme" || !slices.Equal(e.Tags, []string{"work"}) || e.Source != SourceExplicit {
+		t.Fatalf("unexpected audit entry %+v", e)
+	}
+}
+
+// TestSaveProfileConflict checks that a profile version is only saved over
+// the version it revises.
+func TestSaveProfileConflict(t *testing.T) {
+	s, err := NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	first, err := s.SaveProfile("first", []string{"a"}, map[string]string{"a": "hash"}, 0)
+	if err != nil || first.Version != 1 {
+		t.Fatalf("save first: %+v, %v", first, err)
+	}
+	if _, err := s.SaveProfile("racing first", nil, nil, 0); !errors.Is(err, ErrProfileChanged) {
+		t.Fatalf("expected ErrProfileChanged, got %v", err)
+	}
+	second, err := s.SaveProfile("second", nil, nil, first.Version)
+	if err != nil || second.Version != 2 {
+		t.Fatalf("save second: %+v, %v", second, err)
+	}
+
+	latest, err := s.GetProfile()
+	if err != nil || latest.Version != 2 || latest.Content != "second" {
+		t.Fatalf("expected version 2 to be latest, got %+v, %v", latest, err)
+	}
+}
+
+// TestGetTags checks that tags of active memories are listed once whatever
+// their case, most used first.
+func TestGetTags(t *testing.T) {
+	s, err := NewStoreWithPath(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	for _, m := range []*MemoryItem{
+		{Text: "prefers gofmt on save", Tags: []string{"go", "editor"}},
+		{Text: "uses gopls", Tags: []string{"Go", "editor"}},
+		{Text: "writes table-driven tests", Tags: []string{"GO"}},
+		{Text: "formats python with black", Tags: []string{"python"}},
+		{Text: "theme is solarized"},
+		{Text: "may like Rust", Tags: []string{"rust"}, Status: StatusPending},
+		{Text: "used Java once", Tags: []string{"java", "go"}, Status: StatusArchived},
+	} {
+		m.Source = SourceExplicit
+		if err := s.SaveMemory(m); err != nil {
+			t.Fatalf("save memory: %v", err)
+		}
+	}
+
+	tags, err := s.GetTags()
+	if err != nil {
+		t.Fatalf("get tags: %v", err)
+	}
+	if want := []string{"GO", "editor", "python"}; !slices.Equal(tags, want) {
+		t.Fatalf("got tags %v want %v", tags, want)
+	}
+}
@@ -119,11 +119,47 @@ func (r *ChatRequest) WithSystem(system string) *ChatRequest {
 	return r
 }
 
+// WithJSONSchema forces the model to answer by calling a tool whose input
+// schema is schema, since Anthropic has no JSON response mode. The JSON is the
+// tool call's input; see ToolInput.
+func (r *ChatRequest) WithJSONSchema(schema client.JSONSchema) *ChatRequest {
+	input := anthropic.ToolInputSchemaParam{ExtraFields: make(map[string]any)}
+	for key, value := range schema.Schema {
+		switch key {
+		case "type":
+		case "properties":
+			input.Properties = value
+		case "required":
+			input.Required, _ = value.([]string)
+		default:
+			input.ExtraFields[key] = value
+		}
+	}
+
+	tool := anthropic.ToolParam{Name: schema.Name, InputSchema: input}
+	if schema.Description != "" {
+		tool.Description = anthropic.String(schema.Description)
+	}
+	r.Tools = []anthropic.ToolUnionParam{{OfTool: &tool}}
+	r.ToolChoice = anthropic.ToolChoiceParamOfTool(schema.Name)
+	return r
+}
+
 // ChatResponse embeds Anthropic response and implements client.ChatResponse
 type ChatResponse struct {
 	*anthropic.Message
 }
 
+// ToolInput returns the JSON input of the first call to the named tool.
+func (r *ChatResponse) ToolInput(name string) (string, bool) {
+	for _, block := range r.Content {
+		if block.Type == "tool_use" && block.Name == name {
+			return string(block.Input), true
+		}
+	}
+	return "", false
+}
+
 func (r *ChatResponse) GetContent() any {
 	if len(r.Content) > 0 {
 		return r.Content[0].Text
@@ -2,6 +2,7 @@ package anthropic
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/austiecodes/gomor/internal/client"
 	"github.com/austiecodes/gomor/internal/types"
@@ -11,6 +12,8 @@ type QueryClient struct {
 	c *Client
 }
 
+var _ client.StructuredQueryClient = (*QueryClient)(nil)
+
 func NewQueryClient(apiKey, baseURL string) *QueryClient {
 	return &QueryClient{c: NewClient(apiKey, baseURL)}
 }
@@ -28,6 +31,19 @@ func (q *QueryClient) ChatStreamWithContext(ctx context.Context, model types.Mod
 	return q.c.ChatStream(ctx, req)
 }
 
+func (q *QueryClient) ChatJSON(ctx context.Context, model types.Model, query string, schema client.JSONSchema) (string, error) {
+	req := NewChatRequest(model.ModelID).WithMessages(UserMessage(query)).WithJSONSchema(schema)
+	resp, err := q.c.Chat(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	input, ok := resp.(*ChatResponse).ToolInput(schema.Name)
+	if !ok {
+		return "", fmt.Errorf("model did not call the %s tool", schema.Name)
+	}
+	return input, nil
+}
+
 func (q *QueryClient) ListModels(ctx context.Context) ([]string, error) {
 	return q.c.ListModels(ctx)
 }
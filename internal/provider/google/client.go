@@ -95,6 +95,13 @@ func (r *ChatRequest) WithTemperature(t float64) *ChatRequest {
 	return r
 }
 
+// WithJSONSchema makes the model respond with JSON matching schema.
+func (r *ChatRequest) WithJSONSchema(schema client.JSONSchema) *ChatRequest {
+	r.Config.ResponseMIMEType = "application/json"
+	r.Config.ResponseJsonSchema = schema.Schema
+	return r
+}
+
 // ChatResponse implements client.ChatResponse
 type ChatResponse struct {
 	*genai.GenerateContentResponse
@@ -12,6 +12,8 @@ type QueryClient struct {
 	c *Client
 }
 
+var _ client.StructuredQueryClient = (*QueryClient)(nil)
+
 func NewQueryClient(apiKey, baseURL string) *QueryClient {
 	return &QueryClient{c: NewClient(apiKey, baseURL)}
 }
@@ -37,6 +39,23 @@ func (q *QueryClient) ChatStreamWithContext(ctx context.Context, model types.Mod
 	return q.c.ChatStream(ctx, req)
 }
 
+func (q *QueryClient) ChatJSON(ctx context.Context, model types.Model, query string, schema client.JSONSchema) (string, error) {
+	if q.c == nil {
+		return "", fmt.Errorf("google client not initialized")
+	}
+	req := NewChatRequest(model.ModelID).WithMessages(UserMessage(query)).WithJSONSchema(schema)
+	resp, err := q.c.Chat(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	// The JSON may be split across parts; Text joins them
+	text := resp.(*ChatResponse).Text()
+	if text == "" {
+		return "", fmt.Errorf("empty structured response")
+	}
+	return text, nil
+}
+
 func (q *QueryClient) ListModels(ctx context.Context) ([]string, error) {
 	if q.c == nil {
 		return nil, fmt.Errorf("google client not initialized")
@@ -6,6 +6,7 @@ import (
 	"github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/option"
 	"github.com/openai/openai-go/v3/packages/ssestream"
+	"github.com/openai/openai-go/v3/shared"
 
 	"github.com/austiecodes/gomor/internal/client"
 	"github.com/austiecodes/gomor/internal/types"
@@ -97,6 +98,24 @@ func (r *ChatRequest) WithTemperature(t float64) *ChatRequest {
 	return r
 }
 
+// WithJSONSchema constrains the response to schema using strict structured outputs.
+func (r *ChatRequest) WithJSONSchema(schema client.JSONSchema) *ChatRequest {
+	params := openai.ChatCompletionNewParams(*r)
+	format := shared.ResponseFormatJSONSchemaJSONSchemaParam{
+		Name:   schema.Name,
+		Schema: schema.Schema,
+		Strict: openai.Bool(true),
+	}
+	if schema.Description != "" {
+		format.Description = openai.String(schema.Description)
+	}
+	params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
+		OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{JSONSchema: format},
+	}
+	*r = ChatRequest(params)
+	return r
+}
+
 // ChatResponse embeds OpenAI response and implements client.ChatResponse
 type ChatResponse struct {
 	*openai.ChatCompletion
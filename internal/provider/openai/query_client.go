@@ -2,6 +2,7 @@ package openai
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/austiecodes/gomor/internal/client"
 	"github.com/austiecodes/gomor/internal/types"
@@ -11,6 +12,8 @@ type QueryClient struct {
 	c *Client
 }
 
+var _ client.StructuredQueryClient = (*QueryClient)(nil)
+
 func NewQueryClient(apiKey, baseURL string) *QueryClient {
 	return &QueryClient{c: NewClient(apiKey, baseURL)}
 }
@@ -30,6 +33,19 @@ func (q *QueryClient) ChatStreamWithContext(ctx context.Context, model types.Mod
 	return q.c.ChatStream(ctx, req)
 }
 
+func (q *QueryClient) ChatJSON(ctx context.Context, model types.Model, query string, schema client.JSONSchema) (string, error) {
+	req := NewChatRequest(model.ModelID).WithMessages(UserMessage(query)).WithJSONSchema(schema)
+	resp, err := q.c.Chat(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	content, _ := resp.GetContent().(string)
+	if content == "" {
+		return "", fmt.Errorf("empty structured response")
+	}
+	return content, nil
+}
+
 func (q *QueryClient) ListModels(ctx context.Context) ([]string, error) {
 	return q.c.ListModels(ctx)
 }
@@ -58,6 +58,13 @@ const (
 	FTSStrategyAuto = "auto" // Try direct first, fallback to summary if few results
 )
 
+// Fusion strategy constants
+const (
+	FusionStrategyRRF      = "rrf"      // Reciprocal rank fusion
+	FusionStrategyWeighted = "weighted" // Weighted sum of min-max normalized scores
+	FusionStrategyLegacy   = "legacy"   // Fixed 0.6/0.4 weighting with a boost for matches in both
+)
+
 // MemoryConfig represents the memory/retrieval configuration
 type MemoryConfig struct {
 	MinSimilarity    float64 `json:"min_similarity"`
@@ -65,6 +72,56 @@ type MemoryConfig struct {
 	HistoryTopK      int     `json:"history_top_k"`
 	MaxInjectedChars int     `json:"max_injected_chars"`
 	FTSStrategy      string  `json:"fts_strategy"`
+	FusionStrategy   string  `json:"fusion_strategy"`
+	VectorWeight     float64 `json:"vector_weight"`
+	FTSWeight        float64 `json:"fts_weight"`
+	RRFK             int     `json:"rrf_k"`
+	MMREnabled       bool    `json:"mmr_enabled"`
+	// MMRLambda trades relevance (1) against diversity (0) when MMR is
+	// enabled. It must be between 0 and 1; 0 is kept as set.
+	MMRLambda     float64 `json:"mmr_lambda"`
+	RerankEnabled bool    `json:"rerank_enabled"`
+	RerankTopN    int     `json:"rerank_top_n"`
+	// RerankTimeoutMs bounds the tool_model call that grades the candidates.
+	// A negative value means no limit.
+	RerankTimeoutMs int `json:"rerank_timeout_ms"`
+	// RecencyWeight is the share of the fused score subject to time decay (0 disables it).
+	RecencyWeight       float64 `json:"recency_weight"`
+	RecencyHalfLifeDays float64 `json:"recency_half_life_days"`
+	// RecencyOverrides maps "tag:<name>" or "source:<source>" to a half-life in days.
+	// A half-life of 0 means matching memories never decay.
+	RecencyOverrides map[string]float64 `json:"recency_overrides,omitempty"`
+	// MixedModelSearch also embeds queries with every other model still present
+	// in the store, so memories are found by vector search before a reindex.
+	MixedModelSearch bool `json:"mixed_model_search"`
+	// TransformAnswers and TransformRephrasings set how many hypothetical
+	// answers and rephrasings tool_model writes for each query before vector
+	// search. A negative count turns that kind of transformation off.
+	TransformAnswers     int `json:"transform_answers"`
+	TransformRephrasings int `json:"transform_rephrasings"`
+	// LatencyBudgetMs bounds a whole retrieval. Stages still running when it
+	// runs out are dropped and the response is marked degraded instead of
+	// failing. TransformTimeoutMs bounds each tool_model query rewrite and
+	// EmbedTimeoutMs each query embedding. A negative value means no limit.
+	LatencyBudgetMs    int `json:"latency_budget_ms"`
+	TransformTimeoutMs int `json:"transform_timeout_ms"`
+	EmbedTimeoutMs     int `json:"embed_timeout_ms"`
+	// ExtractMinConfidence drops extracted memories the tool model is less
+	// sure of. ExtractDuplicateSimilarity is the cosine similarity at which an
+	// extracted memory counts as a duplicate of one already stored. Both must
+	// be between 0 and 1; 0 is kept as set.
+	ExtractMinConfidence       float64 `json:"extract_min_confidence"`
+	ExtractDuplicateSimilarity float64 `json:"extract_duplicate_similarity"`
+	// ProfileMinImportance is the importance at which a memory goes into the
+	// profile document; pinned memories always do.
+	ProfileMinImportance float64 `json:"profile_min_importance"`
+}
+
+// MCPConfig represents the MCP server configuration
+type MCPConfig struct {
+	// HTTPToken is the bearer token clients must send to the server started
+	// with `gomor mcp --http`, which refuses to start without one.
+	HTTPToken string `json:"http_token,omitempty"`
 }
 
 // Config represents the application configuration
@@ -72,6 +129,7 @@ type Config struct {
 	Providers ProviderConfigs `json:"providers"`
 	Model     ModelConfig     `json:"model"`
 	Memory    MemoryConfig    `json:"memory"`
+	MCP       MCPConfig       `json:"mcp"`
 	Debug     bool            `json:"debug,omitempty"`
 }
 
@@ -106,42 +164,55 @@ func DefaultConfig() *Config {
 			},
 		},
 		Memory: MemoryConfig{
-			MinSimilarity:    0.40,
-			MemoryTopK:       10,
-			HistoryTopK:      10,
-			MaxInjectedChars: 4000,
-			FTSStrategy:      FTSStrategyAuto,
+			MinSimilarity:       0.40,
+			MemoryTopK:          10,
+			HistoryTopK:         10,
+			MaxInjectedChars:    4000,
+			FTSStrategy:         FTSStrategyAuto,
+			FusionStrategy:      FusionStrategyLegacy,
+			VectorWeight:        0.6,
+			FTSWeight:           0.4,
+			RRFK:                60,
+			MMREnabled:          false,
+			MMRLambda:           0.7,
+			RerankEnabled:       false,
+			RerankTopN:          10,
+			RerankTimeoutMs:     3000,
+			RecencyWeight:       0,
+			RecencyHalfLifeDays: 90,
+			RecencyOverrides: map[string]float64{
+				"tag:identity": 0,
+			},
+			MixedModelSearch:           false,
+			TransformAnswers:           1,
+			TransformRephrasings:       1,
+			LatencyBudgetMs:            8000,
+			TransformTimeoutMs:         3000,
+			EmbedTimeoutMs:             3000,
+			ExtractMinConfidence:       0.6,
+			ExtractDuplicateSimilarity: 0.9,
+			ProfileMinImportance:       0.7,
 		},
 		Debug: false,
 	}
 }
 
-// GetConfigPath returns the path to the configuration file
+// GetConfigPath returns the path to the configuration file of the current profile
 func GetConfigPath() (string, error) {
-	homeDir, err := os.UserHomeDir()
+	dir, err := GetProfileDir()
 	if err != nil {
-		return "", fmt.Errorf("failed to get user home directory: %v", err)
-	}
-	gDir := filepath.Join(homeDir, GomorDir)
-	if err := os.MkdirAll(gDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create gomor directory: %v", err)
+		return "", err
 	}
-	return filepath.Join(gDir, SettingFile), nil
+	return filepath.Join(dir, SettingFile), nil
 }
 
-// GetDBPath returns the path to the memory database file.
+// GetDBPath returns the path to the memory database file of the current profile.
 func GetDBPath() (string, error) {
-	homeDir, err := os.UserHomeDir()
+	dir, err := GetProfileDir()
 	if err != nil {
-		return "", fmt.Errorf("failed to get user home directory: %w", err)
+		return "", err
 	}
-
-	gDir := filepath.Join(homeDir, GomorDir)
-	if err := os.MkdirAll(gDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create gomor directory: %w", err)
-	}
-
-	return filepath.Join(gDir, DBFile), nil
+	return filepath.Join(dir, DBFile), nil
 }
 
 // LoadConfig loads the configuration from file
@@ -150,7 +221,11 @@ func LoadConfig() (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	return LoadConfigFrom(configPath)
+}
 
+// LoadConfigFrom loads the configuration from the file at configPath
+func LoadConfigFrom(configPath string) (*Config, error) {
 	// If config file doesn't exist, return default config
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		return DefaultConfig(), nil
@@ -161,10 +236,34 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %v", err)
 	}
 
-	var config Config
+	// Settings for which 0 is meaningful get their default before parsing,
+	// so that only a missing key means the default
+	defaultConfig := DefaultConfig()
+	config := Config{Memory: MemoryConfig{
+		MMRLambda:                  defaultConfig.Memory.MMRLambda,
+		ExtractMinConfidence:       defaultConfig.Memory.ExtractMinConfidence,
+		ExtractDuplicateSimilarity: defaultConfig.Memory.ExtractDuplicateSimilarity,
+	}}
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %v", err)
 	}
+	for _, setting := range []struct {
+		name  string
+		value float64
+	}{
+		{"mmr_lambda", config.Memory.MMRLambda},
+		{"extract_min_confidence", config.Memory.ExtractMinConfidence},
+		{"extract_duplicate_similarity", config.Memory.ExtractDuplicateSimilarity},
+	} {
+		if setting.value < 0 || setting.value > 1 {
+			return nil, fmt.Errorf("memory.%s must be between 0 and 1, got %g", setting.name, setting.value)
+		}
+	}
+	switch config.Memory.FusionStrategy {
+	case "", FusionStrategyRRF, FusionStrategyWeighted, FusionStrategyLegacy:
+	default:
+		return nil, fmt.Errorf("memory.fusion_strategy must be one of: rrf, weighted, legacy, got %q", config.Memory.FusionStrategy)
+	}
 
 	// Apply defaults for missing fields
 	applyDefaults(&config)
@@ -209,6 +308,46 @@ func applyDefaults(config *Config) {
 	if config.Memory.FTSStrategy == "" {
 		config.Memory.FTSStrategy = defaultConfig.Memory.FTSStrategy
 	}
+	if config.Memory.FusionStrategy == "" {
+		config.Memory.FusionStrategy = defaultConfig.Memory.FusionStrategy
+	}
+	if config.Memory.VectorWeight == 0 && config.Memory.FTSWeight == 0 {
+		config.Memory.VectorWeight = defaultConfig.Memory.VectorWeight
+		config.Memory.FTSWeight = defaultConfig.Memory.FTSWeight
+	}
+	if config.Memory.RRFK == 0 {
+		config.Memory.RRFK = defaultConfig.Memory.RRFK
+	}
+	if config.Memory.RerankTopN == 0 {
+		config.Memory.RerankTopN = defaultConfig.Memory.RerankTopN
+	}
+	if config.Memory.RerankTimeoutMs == 0 {
+		config.Memory.RerankTimeoutMs = defaultConfig.Memory.RerankTimeoutMs
+	}
+	if config.Memory.RecencyHalfLifeDays == 0 {
+		config.Memory.RecencyHalfLifeDays = defaultConfig.Memory.RecencyHalfLifeDays
+	}
+	if config.Memory.RecencyOverrides == nil {
+		config.Memory.RecencyOverrides = defaultConfig.Memory.RecencyOverrides
+	}
+	if config.Memory.TransformAnswers == 0 {
+		config.Memory.TransformAnswers = defaultConfig.Memory.TransformAnswers
+	}
+	if config.Memory.TransformRephrasings == 0 {
+		config.Memory.TransformRephrasings = defaultConfig.Memory.TransformRephrasings
+	}
+	if config.Memory.LatencyBudgetMs == 0 {
+		config.Memory.LatencyBudgetMs = defaultConfig.Memory.LatencyBudgetMs
+	}
+	if config.Memory.TransformTimeoutMs == 0 {
+		config.Memory.TransformTimeoutMs = defaultConfig.Memory.TransformTimeoutMs
+	}
+	if config.Memory.EmbedTimeoutMs == 0 {
+		config.Memory.EmbedTimeoutMs = defaultConfig.Memory.EmbedTimeoutMs
+	}
+	if config.Memory.ProfileMinImportance == 0 {
+		config.Memory.ProfileMinImportance = defaultConfig.Memory.ProfileMinImportance
+	}
 }
 
 // SaveConfig saves the configuration to file
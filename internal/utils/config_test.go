@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoadConfigFromMMRLambda checks that an explicit mmr_lambda of 0 is kept,
+// a missing one gets the default, and one out of range is rejected.
+func TestLoadConfigFromMMRLambda(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SettingFile)
+	load := func(settings string) (*Config, error) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(settings), 0644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		return LoadConfigFrom(path)
+	}
+
+	config, err := load(`{"memory": {"mmr_enabled": true, "mmr_lambda": 0}}`)
+	if err != nil || config.Memory.MMRLambda != 0 {
+		t.Fatalf("explicit 0: got %+v, %v", config, err)
+	}
+
+	config, err = load(`{"memory": {"mmr_enabled": true}}`)
+	if err != nil || config.Memory.MMRLambda != DefaultConfig().Memory.MMRLambda {
+		t.Fatalf("missing: got %+v, %v", config, err)
+	}
+
+	if _, err := load(`{"memory": {"mmr_lambda": 1.5}}`); err == nil {
+		t.Fatal("expected an error for mmr_lambda 1.5")
+	}
+}
+
+// TestLoadConfigFromExtractThresholds checks that explicit extraction
+// thresholds of 0 are kept and ones out of range are rejected.
+func TestLoadConfigFromExtractThresholds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SettingFile)
+	write := func(settings string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(settings), 0644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+	}
+
+	write(`{"memory": {"extract_min_confidence": 0, "extract_duplicate_similarity": 0}}`)
+	config, err := LoadConfigFrom(path)
+	if err != nil || config.Memory.ExtractMinConfidence != 0 || config.Memory.ExtractDuplicateSimilarity != 0 {
+		t.Fatalf("explicit 0: got %+v, %v", config, err)
+	}
+
+	write(`{"memory": {}}`)
+	config, err = LoadConfigFrom(path)
+	defaults := DefaultConfig().Memory
+	if err != nil || config.Memory.ExtractMinConfidence != defaults.ExtractMinConfidence ||
+		config.Memory.ExtractDuplicateSimilarity != defaults.ExtractDuplicateSimilarity {
+		t.Fatalf("missing: got %+v, %v", config, err)
+	}
+
+	for _, settings := range []string{
+		`{"memory": {"extract_min_confidence": -0.1}}`,
+		`{"memory": {"extract_duplicate_similarity": 1.2}}`,
+	} {
+		write(settings)
+		if _, err := LoadConfigFrom(path); err == nil {
+			t.Fatalf("expected an error for %s", settings)
+		}
+	}
+}
+
+// TestLoadConfigFromFusionStrategy checks that the legacy fusion strategy is
+// the default and that an unknown one is rejected.
+func TestLoadConfigFromFusionStrategy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SettingFile)
+	if err := os.WriteFile(path, []byte(`{"memory": {}}`), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	config, err := LoadConfigFrom(path)
+	if err != nil || config.Memory.FusionStrategy != FusionStrategyLegacy {
+		t.Fatalf("missing: got %+v, %v", config, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"memory": {"fusion_strategy": "rrf2"}}`), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := LoadConfigFrom(path); err == nil || !strings.Contains(err.Error(), "fusion_strategy") {
+		t.Fatalf("expected an error for an unknown fusion_strategy, got %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	ProfilesDir       = "profiles"
+	ActiveProfileFile = "active_profile"
+	DefaultProfile    = "default"
+)
+
+// Environment variables that override the gomor home directory and profile
+const (
+	EnvHome    = "GOMOR_HOME"
+	EnvProfile = "GOMOR_PROFILE"
+)
+
+var profileNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// profileOverride is set from the global --profile flag and takes precedence
+// over GOMOR_PROFILE and the active profile file.
+var profileOverride string
+
+// SetProfile overrides the profile for the current process.
+func SetProfile(name string) {
+	profileOverride = strings.TrimSpace(name)
+}
+
+// ValidateProfileName checks that a profile name is safe to use as a directory name.
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, '.', '_' or '-'", name)
+	}
+	return nil
+}
+
+// GetHomeDir returns the gomor home directory (GOMOR_HOME or ~/.gomor), creating it if needed.
+func GetHomeDir() (string, error) {
+	gDir := os.Getenv(EnvHome)
+	if gDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		gDir = filepath.Join(homeDir, GomorDir)
+	}
+	if err := os.MkdirAll(gDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create gomor directory: %w", err)
+	}
+	return gDir, nil
+}
+
+// CurrentProfile returns the selected profile name.
+// Precedence: --profile flag, GOMOR_PROFILE, active profile file, then "default".
+func CurrentProfile() (string, error) {
+	if profileOverride != "" {
+		return profileOverride, nil
+	}
+	if name := strings.TrimSpace(os.Getenv(EnvProfile)); name != "" {
+		return name, nil
+	}
+
+	gDir, err := GetHomeDir()
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(filepath.Join(gDir, ActiveProfileFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DefaultProfile, nil
+		}
+		return "", fmt.Errorf("failed to read active profile: %w", err)
+	}
+	if name := strings.TrimSpace(string(data)); name != "" {
+		return name, nil
+	}
+	return DefaultProfile, nil
+}
+
+// profileDir returns the directory for a profile without creating it.
+// The default profile lives directly in the gomor home directory so that
+// existing installations keep working unchanged.
+func profileDir(gDir, name string) string {
+	if name == DefaultProfile {
+		return gDir
+	}
+	return filepath.Join(gDir, ProfilesDir, name)
+}
+
+// GetProfileDir returns the directory holding settings and database for the current profile.
+func GetProfileDir() (string, error) {
+	name, err := CurrentProfile()
+	if err != nil {
+		return "", err
+	}
+	if err := ValidateProfileName(name); err != nil {
+		return "", err
+	}
+
+	gDir, err := GetHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := profileDir(gDir, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	return dir, nil
+}
+
+// ListProfiles returns all known profile names, always including "default".
+func ListProfiles() ([]string, error) {
+	gDir, err := GetHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{DefaultProfile}
+	entries, err := os.ReadDir(filepath.Join(gDir, ProfilesDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() && e.Name() != DefaultProfile && ValidateProfileName(e.Name()) == nil {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names[1:])
+	return names, nil
+}
+
+// CreateProfile creates an empty profile directory.
+func CreateProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if name == DefaultProfile {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+
+	gDir, err := GetHomeDir()
+	if err != nil {
+		return err
+	}
+
+	dir := profileDir(gDir, name)
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	return nil
+}
+
+// UseProfile makes the given profile the active one for future invocations.
+func UseProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+
+	gDir, err := GetHomeDir()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(profileDir(gDir, name)); os.IsNotExist(err) {
+		return fmt.Errorf("profile %q does not exist. Run 'gomor profile create %s' first", name, name)
+	}
+
+	if err := os.WriteFile(filepath.Join(gDir, ActiveProfileFile), []byte(name+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write active profile: %w", err)
+	}
+	return nil
+}
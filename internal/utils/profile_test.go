@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestProfileResolution checks that GOMOR_HOME, GOMOR_PROFILE, the --profile
+// override and the active profile file select the expected directories.
+func TestProfileResolution(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(EnvHome, home)
+	t.Setenv(EnvProfile, "")
+	t.Cleanup(func() { SetProfile("") })
+
+	dbPath, err := GetDBPath()
+	if err != nil {
+		t.Fatalf("GetDBPath: %v", err)
+	}
+	if want := filepath.Join(home, DBFile); dbPath != want {
+		t.Fatalf("default profile db path: got %s want %s", dbPath, want)
+	}
+
+	if err := CreateProfile("work"); err != nil {
+		t.Fatalf("CreateProfile: %v", err)
+	}
+	if err := CreateProfile("work"); err == nil {
+		t.Fatal("expected error when creating an existing profile")
+	}
+	if err := UseProfile("work"); err != nil {
+		t.Fatalf("UseProfile: %v", err)
+	}
+
+	configPath, err := GetConfigPath()
+	if err != nil {
+		t.Fatalf("GetConfigPath: %v", err)
+	}
+	if want := filepath.Join(home, ProfilesDir, "work", SettingFile); configPath != want {
+		t.Fatalf("active profile config path: got %s want %s", configPath, want)
+	}
+
+	t.Setenv(EnvProfile, "ci")
+	dbPath, _ = GetDBPath()
+	if want := filepath.Join(home, ProfilesDir, "ci", DBFile); dbPath != want {
+		t.Fatalf("env profile db path: got %s want %s", dbPath, want)
+	}
+
+	SetProfile(DefaultProfile)
+	dbPath, _ = GetDBPath()
+	if want := filepath.Join(home, DBFile); dbPath != want {
+		t.Fatalf("flag profile db path: got %s want %s", dbPath, want)
+	}
+
+	names, err := ListProfiles()
+	if err != nil {
+		t.Fatalf("ListProfiles: %v", err)
+	}
+	if len(names) != 3 || names[0] != DefaultProfile || names[1] != "ci" || names[2] != "work" {
+		t.Fatalf("unexpected profiles: %v", names)
+	}
+
+	if err := UseProfile("missing"); err == nil {
+		t.Fatal("expected error when using a missing profile")
+	}
+	if err := CreateProfile("../escape"); err == nil {
+		t.Fatal("expected error for invalid profile name")
+	}
+}